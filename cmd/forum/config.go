@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/slowlog"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,15 +19,43 @@ const (
 
 type Conf struct {
 	Server struct {
-		Port int
+		Port         int
+		DrainTimeout time.Duration
+		// ReadinessGrace is how long the server keeps serving after it starts
+		// failing readiness, so load balancers stop routing to it first.
+		ReadinessGrace time.Duration
 	}
-	Database DBConnConfig
+	Database   DBConnConfig
+	Migrations struct {
+		Dir string
+	}
+	Moderation struct {
+		Token string
+	}
+	Service struct {
+		ClearEnabled bool
+	}
+	Auth struct {
+		Required bool
+		TokenTTL time.Duration
+		Admins   []string
+	}
+	Idempotency struct {
+		TTL           time.Duration
+		Lease         time.Duration
+		PurgeInterval time.Duration
+	}
+	Logging     LoggingConfig
+	Tracing     tracing.Config
+	SlowQueries slowlog.Config
 }
 
 func defaultConf() Conf {
 	conf := Conf{}
 
 	conf.Server.Port = 8080
+	conf.Server.DrainTimeout = 30_000_000_000
+	conf.Server.ReadinessGrace = 5_000_000_000
 
 	conf.Database.Host = "localhost"
 	conf.Database.Name = "postgres"
@@ -35,6 +66,34 @@ func defaultConf() Conf {
 	conf.Database.MinConns = 2
 	conf.Database.MaxIdleTimeNS = 60_000_000_000
 
+	conf.Migrations.Dir = "./configs/sql/migrations"
+
+	conf.Auth.Required = false
+	conf.Auth.TokenTTL = 86_400_000_000_000
+
+	conf.Idempotency.TTL = 86_400_000_000_000
+	conf.Idempotency.Lease = 30_000_000_000
+	conf.Idempotency.PurgeInterval = 600_000_000_000
+
+	conf.Logging.Level = "warn"
+	conf.Logging.Format = "text"
+	conf.Logging.Output = "stderr"
+	conf.Logging.File = "./logs/forum.log"
+	conf.Logging.MaxSizeMB = 100
+	conf.Logging.MaxBackups = 5
+	conf.Logging.MaxAgeDays = 7
+	conf.Logging.SampleEvery = 100
+	conf.Logging.Layers = map[string]string{}
+
+	conf.Tracing.Exporter = tracing.ExporterNone
+	conf.Tracing.Endpoint = "localhost:4318"
+	conf.Tracing.Insecure = true
+	conf.Tracing.SampleRatio = 1
+	conf.Tracing.ServiceName = "forum"
+
+	conf.SlowQueries.ExplainEvery = 10
+	conf.SlowQueries.Keep = 50
+
 	return conf
 }
 
@@ -71,6 +130,12 @@ func getConfig(ctx context.Context) (*Conf, error) {
 			if port, ok := serverConf["port"].(int64); ok {
 				conf.Server.Port = int(port)
 			}
+			if drainTimeoutNS, ok := serverConf["drain_timeout_ns"].(int64); ok {
+				conf.Server.DrainTimeout = time.Duration(drainTimeoutNS)
+			}
+			if readinessGraceNS, ok := serverConf["readiness_grace_ns"].(int64); ok {
+				conf.Server.ReadinessGrace = time.Duration(readinessGraceNS)
+			}
 		}
 		if databaseConf, ok := viper.Get("database").(map[string]interface{}); ok {
 			if username, ok := databaseConf["username"].(string); ok {
@@ -98,6 +163,112 @@ func getConfig(ctx context.Context) (*Conf, error) {
 				conf.Database.MaxIdleTimeNS = time.Duration(maxIdleTimeNS)
 			}
 		}
+		if migrationsConf, ok := viper.Get("migrations").(map[string]interface{}); ok {
+			if dir, ok := migrationsConf["dir"].(string); ok {
+				conf.Migrations.Dir = dir
+			}
+		}
+		if moderationConf, ok := viper.Get("moderation").(map[string]interface{}); ok {
+			if token, ok := moderationConf["token"].(string); ok {
+				conf.Moderation.Token = token
+			}
+		}
+		if serviceConf, ok := viper.Get("service").(map[string]interface{}); ok {
+			if clearEnabled, ok := serviceConf["clear_enabled"].(bool); ok {
+				conf.Service.ClearEnabled = clearEnabled
+			}
+		}
+		if authConf, ok := viper.Get("auth").(map[string]interface{}); ok {
+			if required, ok := authConf["required"].(bool); ok {
+				conf.Auth.Required = required
+			}
+			if tokenTTLNS, ok := authConf["token_ttl_ns"].(int64); ok {
+				conf.Auth.TokenTTL = time.Duration(tokenTTLNS)
+			}
+			if admins, ok := authConf["admins"].([]interface{}); ok {
+				conf.Auth.Admins = make([]string, 0, len(admins))
+				for _, admin := range admins {
+					if nickname, ok := admin.(string); ok {
+						conf.Auth.Admins = append(conf.Auth.Admins, nickname)
+					}
+				}
+			}
+		}
+		if idempotencyConf, ok := viper.Get("idempotency").(map[string]interface{}); ok {
+			if ttlNS, ok := idempotencyConf["ttl_ns"].(int64); ok {
+				conf.Idempotency.TTL = time.Duration(ttlNS)
+			}
+			if leaseNS, ok := idempotencyConf["lease_ns"].(int64); ok {
+				conf.Idempotency.Lease = time.Duration(leaseNS)
+			}
+			if purgeIntervalNS, ok := idempotencyConf["purge_interval_ns"].(int64); ok {
+				conf.Idempotency.PurgeInterval = time.Duration(purgeIntervalNS)
+			}
+		}
+		if loggingConf, ok := viper.Get("logging").(map[string]interface{}); ok {
+			if level, ok := loggingConf["level"].(string); ok {
+				conf.Logging.Level = level
+			}
+			if format, ok := loggingConf["format"].(string); ok {
+				conf.Logging.Format = format
+			}
+			if output, ok := loggingConf["output"].(string); ok {
+				conf.Logging.Output = output
+			}
+			if file, ok := loggingConf["file"].(string); ok {
+				conf.Logging.File = file
+			}
+			if maxSizeMB, ok := loggingConf["max_size_mb"].(int64); ok {
+				conf.Logging.MaxSizeMB = int(maxSizeMB)
+			}
+			if maxBackups, ok := loggingConf["max_backups"].(int64); ok {
+				conf.Logging.MaxBackups = int(maxBackups)
+			}
+			if maxAgeDays, ok := loggingConf["max_age_days"].(int64); ok {
+				conf.Logging.MaxAgeDays = int(maxAgeDays)
+			}
+			if sampleEvery, ok := loggingConf["sample_every"].(int64); ok {
+				conf.Logging.SampleEvery = int(sampleEvery)
+			}
+			if layers, ok := loggingConf["layers"].(map[string]interface{}); ok {
+				for layer, level := range layers {
+					if level, ok := level.(string); ok {
+						conf.Logging.Layers[layer] = level
+					}
+				}
+			}
+		}
+		if tracingConf, ok := viper.Get("tracing").(map[string]interface{}); ok {
+			if exporter, ok := tracingConf["exporter"].(string); ok {
+				conf.Tracing.Exporter = exporter
+			}
+			if endpoint, ok := tracingConf["endpoint"].(string); ok {
+				conf.Tracing.Endpoint = endpoint
+			}
+			if insecure, ok := tracingConf["insecure"].(bool); ok {
+				conf.Tracing.Insecure = insecure
+			}
+			if sampleRatio, ok := tracingConf["sample_ratio"].(float64); ok {
+				conf.Tracing.SampleRatio = sampleRatio
+			}
+			if serviceName, ok := tracingConf["service_name"].(string); ok {
+				conf.Tracing.ServiceName = serviceName
+			}
+		}
+		if slowQueriesConf, ok := viper.Get("slow_queries").(map[string]interface{}); ok {
+			if thresholdNS, ok := slowQueriesConf["threshold_ns"].(int64); ok {
+				conf.SlowQueries.Threshold = time.Duration(thresholdNS)
+			}
+			if explain, ok := slowQueriesConf["explain"].(bool); ok {
+				conf.SlowQueries.Explain = explain
+			}
+			if explainEvery, ok := slowQueriesConf["explain_every"].(int64); ok {
+				conf.SlowQueries.ExplainEvery = int(explainEvery)
+			}
+			if keep, ok := slowQueriesConf["keep"].(int64); ok {
+				conf.SlowQueries.Keep = int(keep)
+			}
+		}
 	}
 
 	if err := viper.BindEnv("SERVER_PORT"); err == nil {
@@ -108,6 +279,22 @@ func getConfig(ctx context.Context) (*Conf, error) {
 			}
 		}
 	}
+	if err := viper.BindEnv("SERVER_DRAIN_TIMEOUT"); err == nil {
+		viper.SetDefault("SERVER_DRAIN_TIMEOUT", conf.Server.DrainTimeout)
+		if drainTimeoutNS, ok := viper.Get("SERVER_DRAIN_TIMEOUT").(string); ok {
+			if parsed, err := strconv.ParseInt(drainTimeoutNS, 10, 64); err == nil {
+				conf.Server.DrainTimeout = time.Duration(parsed)
+			}
+		}
+	}
+	if err := viper.BindEnv("SERVER_READINESS_GRACE"); err == nil {
+		viper.SetDefault("SERVER_READINESS_GRACE", conf.Server.ReadinessGrace)
+		if readinessGraceNS, ok := viper.Get("SERVER_READINESS_GRACE").(string); ok {
+			if parsed, err := strconv.ParseInt(readinessGraceNS, 10, 64); err == nil {
+				conf.Server.ReadinessGrace = time.Duration(parsed)
+			}
+		}
+	}
 	if err := viper.BindEnv("DATABASE_NAME"); err == nil {
 		viper.SetDefault("DATABASE_NAME", conf.Database.Name)
 		if name, ok := viper.Get("DATABASE_NAME").(string); ok {
@@ -164,6 +351,203 @@ func getConfig(ctx context.Context) (*Conf, error) {
 			}
 		}
 	}
+	if err := viper.BindEnv("MIGRATIONS_DIR"); err == nil {
+		viper.SetDefault("MIGRATIONS_DIR", conf.Migrations.Dir)
+		if dir, ok := viper.Get("MIGRATIONS_DIR").(string); ok {
+			conf.Migrations.Dir = dir
+		}
+	}
+	if err := viper.BindEnv("MODERATION_TOKEN"); err == nil {
+		viper.SetDefault("MODERATION_TOKEN", conf.Moderation.Token)
+		if token, ok := viper.Get("MODERATION_TOKEN").(string); ok {
+			conf.Moderation.Token = token
+		}
+	}
+	if err := viper.BindEnv("SERVICE_CLEAR_ENABLED"); err == nil {
+		viper.SetDefault("SERVICE_CLEAR_ENABLED", conf.Service.ClearEnabled)
+		if clearEnabled, ok := viper.Get("SERVICE_CLEAR_ENABLED").(string); ok {
+			if parsed, err := strconv.ParseBool(clearEnabled); err == nil {
+				conf.Service.ClearEnabled = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("AUTH_REQUIRED"); err == nil {
+		viper.SetDefault("AUTH_REQUIRED", conf.Auth.Required)
+		if required, ok := viper.Get("AUTH_REQUIRED").(string); ok {
+			if parsed, err := strconv.ParseBool(required); err == nil {
+				conf.Auth.Required = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("AUTH_TOKEN_TTL"); err == nil {
+		viper.SetDefault("AUTH_TOKEN_TTL", conf.Auth.TokenTTL)
+		if tokenTTLNS, ok := viper.Get("AUTH_TOKEN_TTL").(string); ok {
+			if parsed, err := strconv.ParseInt(tokenTTLNS, 10, 64); err == nil {
+				conf.Auth.TokenTTL = time.Duration(parsed)
+			}
+		}
+	}
+	if err := viper.BindEnv("AUTH_ADMINS"); err == nil {
+		if admins, ok := viper.Get("AUTH_ADMINS").(string); ok && admins != "" {
+			conf.Auth.Admins = strings.Split(admins, ",")
+		}
+	}
+	if err := viper.BindEnv("IDEMPOTENCY_TTL"); err == nil {
+		viper.SetDefault("IDEMPOTENCY_TTL", conf.Idempotency.TTL)
+		if ttlNS, ok := viper.Get("IDEMPOTENCY_TTL").(string); ok {
+			if parsed, err := strconv.ParseInt(ttlNS, 10, 64); err == nil {
+				conf.Idempotency.TTL = time.Duration(parsed)
+			}
+		}
+	}
+	if err := viper.BindEnv("IDEMPOTENCY_LEASE"); err == nil {
+		viper.SetDefault("IDEMPOTENCY_LEASE", conf.Idempotency.Lease)
+		if leaseNS, ok := viper.Get("IDEMPOTENCY_LEASE").(string); ok {
+			if parsed, err := strconv.ParseInt(leaseNS, 10, 64); err == nil {
+				conf.Idempotency.Lease = time.Duration(parsed)
+			}
+		}
+	}
+	if err := viper.BindEnv("IDEMPOTENCY_PURGE_INTERVAL"); err == nil {
+		viper.SetDefault("IDEMPOTENCY_PURGE_INTERVAL", conf.Idempotency.PurgeInterval)
+		if purgeIntervalNS, ok := viper.Get("IDEMPOTENCY_PURGE_INTERVAL").(string); ok {
+			if parsed, err := strconv.ParseInt(purgeIntervalNS, 10, 64); err == nil {
+				conf.Idempotency.PurgeInterval = time.Duration(parsed)
+			}
+		}
+	}
+
+	if err := viper.BindEnv("LOGGING_LEVEL"); err == nil {
+		viper.SetDefault("LOGGING_LEVEL", conf.Logging.Level)
+		if level, ok := viper.Get("LOGGING_LEVEL").(string); ok {
+			conf.Logging.Level = level
+		}
+	}
+	if err := viper.BindEnv("LOGGING_FORMAT"); err == nil {
+		viper.SetDefault("LOGGING_FORMAT", conf.Logging.Format)
+		if format, ok := viper.Get("LOGGING_FORMAT").(string); ok {
+			conf.Logging.Format = format
+		}
+	}
+	if err := viper.BindEnv("LOGGING_OUTPUT"); err == nil {
+		viper.SetDefault("LOGGING_OUTPUT", conf.Logging.Output)
+		if output, ok := viper.Get("LOGGING_OUTPUT").(string); ok {
+			conf.Logging.Output = output
+		}
+	}
+	if err := viper.BindEnv("LOGGING_FILE"); err == nil {
+		viper.SetDefault("LOGGING_FILE", conf.Logging.File)
+		if file, ok := viper.Get("LOGGING_FILE").(string); ok {
+			conf.Logging.File = file
+		}
+	}
+	if err := viper.BindEnv("LOGGING_MAX_SIZE_MB"); err == nil {
+		viper.SetDefault("LOGGING_MAX_SIZE_MB", conf.Logging.MaxSizeMB)
+		if maxSizeMB, ok := viper.Get("LOGGING_MAX_SIZE_MB").(string); ok {
+			if parsed, err := strconv.Atoi(maxSizeMB); err == nil {
+				conf.Logging.MaxSizeMB = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("LOGGING_MAX_BACKUPS"); err == nil {
+		viper.SetDefault("LOGGING_MAX_BACKUPS", conf.Logging.MaxBackups)
+		if maxBackups, ok := viper.Get("LOGGING_MAX_BACKUPS").(string); ok {
+			if parsed, err := strconv.Atoi(maxBackups); err == nil {
+				conf.Logging.MaxBackups = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("LOGGING_MAX_AGE_DAYS"); err == nil {
+		viper.SetDefault("LOGGING_MAX_AGE_DAYS", conf.Logging.MaxAgeDays)
+		if maxAgeDays, ok := viper.Get("LOGGING_MAX_AGE_DAYS").(string); ok {
+			if parsed, err := strconv.Atoi(maxAgeDays); err == nil {
+				conf.Logging.MaxAgeDays = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("LOGGING_SAMPLE_EVERY"); err == nil {
+		viper.SetDefault("LOGGING_SAMPLE_EVERY", conf.Logging.SampleEvery)
+		if sampleEvery, ok := viper.Get("LOGGING_SAMPLE_EVERY").(string); ok {
+			if parsed, err := strconv.Atoi(sampleEvery); err == nil {
+				conf.Logging.SampleEvery = parsed
+			}
+		}
+	}
+	for _, layer := range logLayers {
+		env := "LOGGING_LEVEL_" + strings.ToUpper(layer.name)
+		if err := viper.BindEnv(env); err == nil {
+			if level, ok := viper.Get(env).(string); ok && level != "" {
+				conf.Logging.Layers[layer.name] = level
+			}
+		}
+	}
+
+	if err := viper.BindEnv("TRACING_EXPORTER"); err == nil {
+		viper.SetDefault("TRACING_EXPORTER", conf.Tracing.Exporter)
+		if exporter, ok := viper.Get("TRACING_EXPORTER").(string); ok {
+			conf.Tracing.Exporter = exporter
+		}
+	}
+	if err := viper.BindEnv("TRACING_ENDPOINT"); err == nil {
+		viper.SetDefault("TRACING_ENDPOINT", conf.Tracing.Endpoint)
+		if endpoint, ok := viper.Get("TRACING_ENDPOINT").(string); ok {
+			conf.Tracing.Endpoint = endpoint
+		}
+	}
+	if err := viper.BindEnv("TRACING_INSECURE"); err == nil {
+		viper.SetDefault("TRACING_INSECURE", conf.Tracing.Insecure)
+		if insecure, ok := viper.Get("TRACING_INSECURE").(string); ok {
+			if parsed, err := strconv.ParseBool(insecure); err == nil {
+				conf.Tracing.Insecure = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("TRACING_SAMPLE_RATIO"); err == nil {
+		viper.SetDefault("TRACING_SAMPLE_RATIO", conf.Tracing.SampleRatio)
+		if sampleRatio, ok := viper.Get("TRACING_SAMPLE_RATIO").(string); ok {
+			if parsed, err := strconv.ParseFloat(sampleRatio, 64); err == nil {
+				conf.Tracing.SampleRatio = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("TRACING_SERVICE_NAME"); err == nil {
+		viper.SetDefault("TRACING_SERVICE_NAME", conf.Tracing.ServiceName)
+		if serviceName, ok := viper.Get("TRACING_SERVICE_NAME").(string); ok {
+			conf.Tracing.ServiceName = serviceName
+		}
+	}
+	if err := viper.BindEnv("SLOW_QUERIES_THRESHOLD"); err == nil {
+		viper.SetDefault("SLOW_QUERIES_THRESHOLD", conf.SlowQueries.Threshold)
+		if thresholdNS, ok := viper.Get("SLOW_QUERIES_THRESHOLD").(string); ok {
+			if parsed, err := strconv.ParseInt(thresholdNS, 10, 64); err == nil {
+				conf.SlowQueries.Threshold = time.Duration(parsed)
+			}
+		}
+	}
+	if err := viper.BindEnv("SLOW_QUERIES_EXPLAIN"); err == nil {
+		viper.SetDefault("SLOW_QUERIES_EXPLAIN", conf.SlowQueries.Explain)
+		if explain, ok := viper.Get("SLOW_QUERIES_EXPLAIN").(string); ok {
+			if parsed, err := strconv.ParseBool(explain); err == nil {
+				conf.SlowQueries.Explain = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("SLOW_QUERIES_EXPLAIN_EVERY"); err == nil {
+		viper.SetDefault("SLOW_QUERIES_EXPLAIN_EVERY", conf.SlowQueries.ExplainEvery)
+		if explainEvery, ok := viper.Get("SLOW_QUERIES_EXPLAIN_EVERY").(string); ok {
+			if parsed, err := strconv.Atoi(explainEvery); err == nil {
+				conf.SlowQueries.ExplainEvery = parsed
+			}
+		}
+	}
+	if err := viper.BindEnv("SLOW_QUERIES_KEEP"); err == nil {
+		viper.SetDefault("SLOW_QUERIES_KEEP", conf.SlowQueries.Keep)
+		if keep, ok := viper.Get("SLOW_QUERIES_KEEP").(string); ok {
+			if parsed, err := strconv.Atoi(keep); err == nil {
+				conf.SlowQueries.Keep = parsed
+			}
+		}
+	}
 
 	return &conf, nil
 }
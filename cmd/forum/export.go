@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	ExportRepo "github.com/rflban/parkmail-dbms/internal/forum/export/repository"
+	ExportUseCase "github.com/rflban/parkmail-dbms/internal/forum/export/usecase"
+	ForumRepo "github.com/rflban/parkmail-dbms/internal/forum/forums/repository"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"io"
+	"os"
+)
+
+const exportUsage = "usage: forum export --forum SLUG [-format ndjson|tar] [-o FILE]"
+
+func RunExport(ctx context.Context, db *pgxhook.Pool, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	slug := flags.String("forum", "", "slug of the forum to export")
+	format := flags.String("format", bundle.FormatNDJSON, "ndjson or tar")
+	output := flags.String("o", "-", "file to write the export to, - for stdout")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *slug == "" {
+		return fmt.Errorf(exportUsage)
+	}
+
+	// Whoever runs the command has access to the database anyway.
+	ctx = context.WithValue(ctx, constants.PrivilegedKey, true)
+
+	exportUseCase := ExportUseCase.New(ExportRepo.New(db), ForumRepo.New(db))
+
+	write, err := exportUseCase.Export(ctx, *slug, *format)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if *output != "-" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	buffered := bufio.NewWriterSize(out, 1<<20)
+	if err = write(buffered); err != nil {
+		return err
+	}
+	if err = buffered.Flush(); err != nil {
+		return err
+	}
+
+	if out == os.Stdout {
+		return nil
+	}
+	return out.Close()
+}
@@ -3,13 +3,23 @@ package main
 import (
 	"context"
 	FasthttpRouter "github.com/fasthttp/router"
-	"github.com/jackc/pgx/v4/pgxpool"
+	AuthDelivery "github.com/rflban/parkmail-dbms/internal/forum/auth/delivery"
+	AuthRepo "github.com/rflban/parkmail-dbms/internal/forum/auth/repository"
+	AuthUseCase "github.com/rflban/parkmail-dbms/internal/forum/auth/usecase"
+	ExportDelivery "github.com/rflban/parkmail-dbms/internal/forum/export/delivery"
+	ExportRepo "github.com/rflban/parkmail-dbms/internal/forum/export/repository"
+	ExportUseCase "github.com/rflban/parkmail-dbms/internal/forum/export/usecase"
 	ForumDelivery "github.com/rflban/parkmail-dbms/internal/forum/forums/delivery"
 	ForumRepo "github.com/rflban/parkmail-dbms/internal/forum/forums/repository"
 	ForumUseCase "github.com/rflban/parkmail-dbms/internal/forum/forums/usecase"
+	IdempotencyRepo "github.com/rflban/parkmail-dbms/internal/forum/idempotency/repository"
+	IdempotencyUseCase "github.com/rflban/parkmail-dbms/internal/forum/idempotency/usecase"
 	PostDelivery "github.com/rflban/parkmail-dbms/internal/forum/posts/delivery"
 	PostRepo "github.com/rflban/parkmail-dbms/internal/forum/posts/repository"
 	PostUseCase "github.com/rflban/parkmail-dbms/internal/forum/posts/usecase"
+	SearchDelivery "github.com/rflban/parkmail-dbms/internal/forum/search/delivery"
+	SearchRepo "github.com/rflban/parkmail-dbms/internal/forum/search/repository"
+	SearchUseCase "github.com/rflban/parkmail-dbms/internal/forum/search/usecase"
 	ServiceDelivery "github.com/rflban/parkmail-dbms/internal/forum/service/delivery"
 	ServiceRepo "github.com/rflban/parkmail-dbms/internal/forum/service/repository"
 	ServiceUseCase "github.com/rflban/parkmail-dbms/internal/forum/service/usecase"
@@ -21,31 +31,49 @@ import (
 	UserUseCase "github.com/rflban/parkmail-dbms/internal/forum/users/usecase"
 	VoteRepo "github.com/rflban/parkmail-dbms/internal/forum/votes/repository"
 	VoteUseCase "github.com/rflban/parkmail-dbms/internal/forum/votes/usecase"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/migrations"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/slowlog"
+	"github.com/valyala/fasthttp"
 )
 
 const prefix = "/api"
 
-func SetupHandlers(ctx context.Context, pool *pgxpool.Pool, router *FasthttpRouter.Router) {
+// SetupHandlers registers every route and returns the function that makes
+// the service stop reporting itself ready once shutdown begins.
+func SetupHandlers(ctx context.Context, conf *Conf, db *pgxhook.Pool, migrator *migrations.Migrator, slowQueries *slowlog.Log, router *FasthttpRouter.Router) func() {
 	var (
-		serviceRepo = ServiceRepo.New(pool)
-		userRepo    = UserRepo.New(pool)
-		voteRepo    = VoteRepo.New(pool)
-		forumRepo   = ForumRepo.New(pool)
-		threadRepo  = ThreadRepo.New(pool)
-		postRepo    = PostRepo.New(pool)
+		authRepo        = AuthRepo.New(db)
+		exportRepo      = ExportRepo.New(db)
+		idempotencyRepo = IdempotencyRepo.New(db)
+		searchRepo      = SearchRepo.New(db)
+		serviceRepo     = ServiceRepo.New(db)
+		userRepo        = UserRepo.New(db)
+		voteRepo        = VoteRepo.New(db)
+		forumRepo       = ForumRepo.New(db)
+		threadRepo      = ThreadRepo.New(db)
+		postRepo        = PostRepo.New(db)
 	)
 
 	var (
-		serviceUseCase = ServiceUseCase.New(serviceRepo)
-		userUseCase    = UserUseCase.New(userRepo)
-		voteUseCase    = VoteUseCase.New(voteRepo, threadRepo)
-		forumUseCase   = ForumUseCase.New(forumRepo)
-		threadUseCase  = ThreadUseCase.New(threadRepo, forumRepo, userRepo)
-		postUseCase    = PostUseCase.New(postRepo, userRepo, threadRepo, forumRepo)
+		authUseCase        = AuthUseCase.New(authRepo, conf.Auth.TokenTTL, conf.Auth.Admins)
+		exportUseCase      = ExportUseCase.New(exportRepo, forumRepo)
+		idempotencyUseCase = IdempotencyUseCase.New(idempotencyRepo, conf.Idempotency.TTL, conf.Idempotency.Lease)
+		searchUseCase      = SearchUseCase.New(searchRepo)
+		serviceUseCase     = ServiceUseCase.New(serviceRepo, migrator, slowQueries, conf.Service.ClearEnabled)
+		userUseCase        = UserUseCase.New(userRepo)
+		voteUseCase        = VoteUseCase.New(voteRepo, threadRepo)
+		forumUseCase       = ForumUseCase.New(forumRepo)
+		threadUseCase      = ThreadUseCase.New(threadRepo, forumRepo, userRepo)
+		postUseCase        = PostUseCase.New(postRepo, userRepo, threadRepo, forumRepo)
 	)
 
 	var (
+		authHandler    = AuthDelivery.New(authUseCase)
+		exportHandler  = ExportDelivery.New(exportUseCase)
+		searchHandler  = SearchDelivery.New(searchUseCase)
 		serviceHandler = ServiceDelivery.New(serviceUseCase)
 		userHandler    = UserDelivery.New(userUseCase)
 		forumHandler   = ForumDelivery.New(forumUseCase, threadUseCase)
@@ -53,25 +81,68 @@ func SetupHandlers(ctx context.Context, pool *pgxpool.Pool, router *FasthttpRout
 		postHandler    = PostDelivery.New(postUseCase)
 	)
 
-	router.POST(prefix+"/forum/create", middlewares.AccessLog(forumHandler.Create))
+	if conf.Idempotency.PurgeInterval > 0 {
+		go idempotencyUseCase.PurgeExpired(ctx, conf.Idempotency.PurgeInterval)
+	}
+
+	identify := func(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+		return middlewares.Auth(authUseCase, false, next)
+	}
+	authenticate := func(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+		return middlewares.Auth(authUseCase, conf.Auth.Required, next)
+	}
+	requireCaller := func(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+		return middlewares.Auth(authUseCase, true, next)
+	}
+	idempotent := func(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+		return middlewares.Idempotency(idempotencyUseCase, next)
+	}
+
+	router.GET("/healthz", serviceHandler.Health)
+	router.GET("/readyz", serviceHandler.Ready)
+	router.GET("/metrics", metrics.Handler())
+
+	router.POST(prefix+"/auth/credentials", middlewares.AccessLog(requireCaller(authHandler.SetCredentials)))
+	router.POST(prefix+"/auth/token", middlewares.AccessLog(authHandler.IssueToken))
+
+	router.POST(prefix+"/forum/create", middlewares.AccessLog(authenticate(idempotent(forumHandler.Create))))
 	router.GET(prefix+"/forum/{slug}/details", middlewares.AccessLog(forumHandler.GetDetails))
-	router.POST(prefix+"/forum/{slug}/create", middlewares.AccessLog(forumHandler.CreateThread))
+	router.POST(prefix+"/forum/{slug}/create", middlewares.AccessLog(authenticate(idempotent(forumHandler.CreateThread))))
 	router.GET(prefix+"/forum/{slug}/users", middlewares.AccessLog(forumHandler.GetUsers))
 	router.GET(prefix+"/forum/{slug}/threads", middlewares.AccessLog(forumHandler.GetThreads))
+	router.DELETE(prefix+"/forum/{slug}", middlewares.AccessLog(authenticate(forumHandler.Delete)))
+	router.GET(prefix+"/forum/{slug}/export", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(exportHandler.Export))))
+	router.GET(prefix+"/forum/{slug}/moderators", middlewares.AccessLog(forumHandler.GetModerators))
+	router.POST(prefix+"/forum/{slug}/moderators/{nickname}", middlewares.AccessLog(authenticate(forumHandler.AddModerator)))
+	router.DELETE(prefix+"/forum/{slug}/moderators/{nickname}", middlewares.AccessLog(authenticate(forumHandler.RemoveModerator)))
+
+	router.GET(prefix+"/post/{id}/details", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.GetDetails))))
+	router.POST(prefix+"/post/{id}/details", middlewares.AccessLog(authenticate(postHandler.Edit)))
+	router.GET(prefix+"/post/{id}/ancestors", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.GetAncestors))))
+	router.GET(prefix+"/post/{id}/replies", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.GetReplies))))
+	router.GET(prefix+"/post/{id}/revisions", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.GetRevisions))))
+	router.GET(prefix+"/post/{id}/revisions/{n}/diff", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.GetRevisionDiff))))
+	router.POST(prefix+"/post/{id}/moderate", middlewares.AccessLog(middlewares.Moderator(conf.Moderation.Token, identify(postHandler.Moderate))))
+	router.DELETE(prefix+"/post/{id}", middlewares.AccessLog(authenticate(postHandler.Delete)))
 
-	router.GET(prefix+"/post/{id}/details", middlewares.AccessLog(postHandler.GetDetails))
-	router.POST(prefix+"/post/{id}/details", middlewares.AccessLog(postHandler.Edit))
+	router.GET(prefix+"/search", middlewares.AccessLog(searchHandler.Search))
 
-	router.POST(prefix+"/service/clear", middlewares.AccessLog(serviceHandler.Clear))
+	router.POST(prefix+"/service/clear", middlewares.AccessLog(authenticate(serviceHandler.Clear)))
 	router.GET(prefix+"/service/status", middlewares.AccessLog(serviceHandler.Status))
+	router.GET(prefix+"/service/slow-queries", middlewares.AccessLog(identify(serviceHandler.SlowQueries)))
 
-	router.POST(prefix+"/thread/{slug_or_id}/create", middlewares.AccessLog(threadHandler.CreatePosts))
+	router.POST(prefix+"/thread/{slug_or_id}/create", middlewares.AccessLog(authenticate(idempotent(threadHandler.CreatePosts))))
 	router.GET(prefix+"/thread/{slug_or_id}/details", middlewares.AccessLog(threadHandler.GetDetails))
-	router.POST(prefix+"/thread/{slug_or_id}/details", middlewares.AccessLog(threadHandler.Edit))
+	router.POST(prefix+"/thread/{slug_or_id}/details", middlewares.AccessLog(authenticate(threadHandler.Edit)))
+	router.GET(prefix+"/thread/{slug_or_id}/revisions", middlewares.AccessLog(threadHandler.GetRevisions))
 	router.GET(prefix+"/thread/{slug_or_id}/posts", middlewares.AccessLog(threadHandler.GetPosts))
-	router.POST(prefix+"/thread/{slug_or_id}/vote", middlewares.AccessLog(threadHandler.Vote))
+	router.POST(prefix+"/thread/{slug_or_id}/vote", middlewares.AccessLog(authenticate(threadHandler.Vote)))
+	router.DELETE(prefix+"/thread/{slug_or_id}", middlewares.AccessLog(authenticate(threadHandler.Delete)))
 
-	router.POST(prefix+"/user/{nickname}/create", middlewares.AccessLog(userHandler.Create))
+	router.POST(prefix+"/user/{nickname}/create", middlewares.AccessLog(idempotent(userHandler.Create)))
 	router.GET(prefix+"/user/{nickname}/profile", middlewares.AccessLog(userHandler.GetProfileByNickname))
-	router.POST(prefix+"/user/{nickname}/profile", middlewares.AccessLog(userHandler.EditProfileByNickname))
+	router.POST(prefix+"/user/{nickname}/profile", middlewares.AccessLog(authenticate(userHandler.EditProfileByNickname)))
+	router.DELETE(prefix+"/user/{nickname}", middlewares.AccessLog(authenticate(userHandler.Delete)))
+
+	return serviceUseCase.Drain
 }
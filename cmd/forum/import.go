@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/importer"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+const importUsage = "usage: forum import [-source NAME] [-users FILE] [-forums FILE] [-threads FILE] [-posts FILE] [-votes FILE] | -bundle FILE [-format ndjson|tar]"
+
+func RunImport(ctx context.Context, db *pgxhook.Pool, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	files := make(map[bundle.Kind]*string, len(bundle.Kinds))
+	for _, kind := range bundle.Kinds {
+		files[kind] = flags.String(string(kind), "", "NDJSON file of "+string(kind)+", - for stdin")
+	}
+	bundlePath := flags.String("bundle", "", "forum export to load, - for stdin")
+	format := flags.String("format", bundle.FormatNDJSON, "format of the bundle")
+	source := flags.String("source", "default", "system the legacy ids come from")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || !bundle.IsValidFormat(*format) || *source == "" {
+		return fmt.Errorf(importUsage)
+	}
+
+	stdin := 0
+	if *bundlePath == "-" {
+		stdin++
+	}
+	for _, kind := range bundle.Kinds {
+		if *files[kind] == "-" {
+			stdin++
+		}
+	}
+	if stdin > 1 {
+		return fmt.Errorf("only one input can be read from stdin")
+	}
+
+	loader := importer.New(db, *source, func(kind bundle.Kind, read int64) {
+		fmt.Fprintf(os.Stderr, "%s: %d lines read\n", kind, read)
+	})
+
+	reports := make([]importer.Report, 0, len(bundle.Kinds))
+	if *bundlePath != "" {
+		loaded, err := importBundle(ctx, loader, *bundlePath, *format)
+		reports = append(reports, loaded...)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, kind := range bundle.Kinds {
+		path := *files[kind]
+		if path == "" {
+			continue
+		}
+
+		report, err := importFile(ctx, loader, kind, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: done\n", kind)
+		reports = append(reports, report)
+	}
+
+	if len(reports) == 0 {
+		return fmt.Errorf(importUsage)
+	}
+
+	if err := loader.FixCounters(ctx); err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tREAD\tIMPORTED\tREJECTED")
+	for _, report := range reports {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", report.Kind, report.Read, report.Imported, report.Rejected)
+	}
+
+	return writer.Flush()
+}
+
+func importFile(ctx context.Context, loader *importer.Importer, kind bundle.Kind, path string) (importer.Report, error) {
+	if path == "-" {
+		return loader.Import(ctx, kind, os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return importer.Report{Kind: kind}, err
+	}
+	defer file.Close()
+
+	return loader.Import(ctx, kind, file)
+}
+
+func importBundle(ctx context.Context, loader *importer.Importer, path string, format string) ([]importer.Report, error) {
+	input := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		input = file
+	}
+
+	reader, err := bundle.NewReader(format, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.ImportBundle(ctx, reader)
+}
@@ -2,41 +2,111 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
 	"io/ioutil"
+	"os"
+	"strings"
 )
 
 const helloMessage = " ________ ________  ________  ___  ___  _____ ______      \n|\\  _____\\\\   __  \\|\\   __  \\|\\  \\|\\  \\|\\   _ \\  _   \\    \n\\ \\  \\__/\\ \\  \\|\\  \\ \\  \\|\\  \\ \\  \\\\\\  \\ \\  \\\\\\__\\ \\  \\   \n \\ \\   __\\\\ \\  \\\\\\  \\ \\   _  _\\ \\  \\\\\\  \\ \\  \\\\|__| \\  \\  \n  \\ \\  \\_| \\ \\  \\\\\\  \\ \\  \\\\  \\\\ \\  \\\\\\  \\ \\  \\    \\ \\  \\ \n   \\ \\__\\   \\ \\_______\\ \\__\\\\ _\\\\ \\_______\\ \\__\\    \\ \\__\\\n    \\|__|    \\|_______|\\|__|\\|__|\\|_______|\\|__|     \\|__|\n                                                          "
 
+type LoggingConfig struct {
+	Level       string
+	Format      string
+	Output      string
+	File        string
+	MaxSizeMB   int
+	MaxBackups  int
+	MaxAgeDays  int
+	SampleEvery int
+	// Layers overrides Level for single layers: setup, access, delivery,
+	// usecase, repo and slowquery.
+	Layers map[string]string
+}
+
+var logLayers = []struct {
+	name string
+	key  string
+}{
+	{"setup", constants.SetupLogKey},
+	{"access", constants.AccessLogKey},
+	{"delivery", constants.DeliveryLogKey},
+	{"usecase", constants.UseCaseLogKey},
+	{"repo", constants.RepoLogKey},
+	{"slowquery", constants.SlowQueryLogKey},
+}
+
+// BindLoggers puts loggers that discard everything into ctx, so that
+// setup code can log before the configuration is read.
 func BindLoggers(ctx context.Context) context.Context {
 	logrus.SetOutput(ioutil.Discard)
 
-	withSetupLog := context.WithValue(
-		ctx,
-		constants.SetupLogKey,
-		logrus.WithField("type", "setup"),
-	)
-	withAccessLog := context.WithValue(
-		withSetupLog,
-		constants.AccessLogKey,
-		logrus.WithField("type", "access"),
-	)
-	withDeliveryLog := context.WithValue(
-		withAccessLog,
-		constants.DeliveryLogKey,
-		logrus.WithField("type", "delivery"),
-	)
-	withUseCaseLog := context.WithValue(
-		withDeliveryLog,
-		constants.UseCaseLogKey,
-		logrus.WithField("type", "usecase"),
-	)
-	withRepoLog := context.WithValue(
-		withUseCaseLog,
-		constants.RepoLogKey,
-		logrus.WithField("type", "repo"),
-	)
-
-	return withRepoLog
+	for _, layer := range logLayers {
+		ctx = context.WithValue(ctx, layer.key, logrus.WithField("type", layer.name))
+	}
+
+	return ctx
+}
+
+// SetupLoggers replaces the loggers of every layer with ones built from
+// conf. The returned function closes the log file, if there is one.
+func SetupLoggers(ctx context.Context, conf LoggingConfig) (context.Context, func() error, error) {
+	level, err := logrus.ParseLevel(conf.Level)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	var formatter logrus.Formatter
+	switch strings.ToLower(conf.Format) {
+	case "json":
+		formatter = &logrus.JSONFormatter{}
+	case "text":
+		formatter = &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}
+	default:
+		return ctx, nil, fmt.Errorf("unknown log format: %s", conf.Format)
+	}
+
+	var out io.Writer
+	closeOut := func() error { return nil }
+
+	switch strings.ToLower(conf.Output) {
+	case "stdout":
+		out = os.Stdout
+	case "stderr":
+		out = os.Stderr
+	case "discard":
+		out = ioutil.Discard
+	case "file":
+		rotated := &lumberjack.Logger{
+			Filename:   conf.File,
+			MaxSize:    conf.MaxSizeMB,
+			MaxBackups: conf.MaxBackups,
+			MaxAge:     conf.MaxAgeDays,
+		}
+		out, closeOut = rotated, rotated.Close
+	default:
+		return ctx, nil, fmt.Errorf("unknown log output: %s", conf.Output)
+	}
+
+	for _, layer := range logLayers {
+		layerLevel := level
+		if override, ok := conf.Layers[layer.name]; ok && override != "" {
+			if layerLevel, err = logrus.ParseLevel(override); err != nil {
+				return ctx, nil, err
+			}
+		}
+
+		logger := logrus.New()
+		logger.SetOutput(out)
+		logger.SetFormatter(formatter)
+		logger.SetLevel(layerLevel)
+
+		ctx = context.WithValue(ctx, layer.key, logger.WithField("type", layer.name))
+	}
+
+	return ctx, closeOut, nil
 }
@@ -4,7 +4,19 @@ import (
 	"context"
 	"fmt"
 	FasthttpRouter "github.com/fasthttp/router"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/migrations"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/slowlog"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -15,28 +27,158 @@ func main() {
 		return
 	}
 
+	ctx, closeLogs, err := SetupLoggers(ctx, conf.Logging)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer closeLogs()
+
+	setupLog := ctx.Value(constants.SetupLogKey).(*logrus.Entry)
+	shutdownTracing, err := tracing.Setup(ctx, conf.Tracing, func(err error) {
+		setupLog.Debugf("tracing: %s", err)
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(flushCtx)
+	}()
+
+	slowQueries := slowlog.New(conf.SlowQueries, ctx.Value(constants.SlowQueryLogKey).(*logrus.Entry))
+
+	var hooks []pgxhook.Hook
+	if conf.Tracing.Enabled() {
+		hooks = append(hooks, tracing.QueryTracer{})
+	}
+	if conf.SlowQueries.Enabled() {
+		hooks = append(hooks, slowQueries)
+	}
+
 	connString := GetConnString(conf.Database)
 	pool, err := SetupDB(ctx, connString)
 	if err != nil {
 		return
 	}
+	slowQueries.Attach(pool)
+	db := pgxhook.New(pool, hooks...)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = RunMigrate(ctx, pool, conf.Migrations.Dir, os.Args[2:])
+		pool.Close()
+
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		err = RunImport(ctx, db, os.Args[2:])
+		pool.Close()
+
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		err = RunExport(ctx, db, os.Args[2:])
+		pool.Close()
+
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	loaded, err := migrations.Load(conf.Migrations.Dir)
+	if err != nil {
+		fmt.Println(err)
+		pool.Close()
+		return
+	}
+
+	// Requests run under their own context so the ones still in flight when
+	// the drain timeout runs out can be cancelled.
+	requestCtx, cancelRequests := context.WithCancel(ctx)
+	defer cancelRequests()
+
+	if err = metrics.Register(metrics.NewPoolCollector(pool)); err != nil {
+		fmt.Println(err)
+		pool.Close()
+		return
+	}
 
 	router := FasthttpRouter.New()
+	router.SaveMatchedRoutePath = true
 
-	SetupHandlers(ctx, pool, router)
+	drain := SetupHandlers(requestCtx, conf, db, migrations.New(pool, loaded), slowQueries, router)
+
+	handler := middlewares.SampleLogs(ctx, conf.Logging.SampleEvery, middlewares.RequestId(router.Handler))
+	if conf.Tracing.Enabled() {
+		handler = middlewares.Tracing(handler)
+	}
+	handler = middlewares.Metrics(handler)
+	server := &fasthttp.Server{
+		Handler: func(fasthttpCtx *fasthttp.RequestCtx) {
+			fasthttpCtx.SetUserValue("ctx", requestCtx)
+			handler(fasthttpCtx)
+		},
+	}
 
 	fmt.Println(helloMessage)
 	fmt.Printf("Server has been started at http://localhost:%d\n", conf.Server.Port)
 
-	err = fasthttp.ListenAndServe(
-		fmt.Sprintf(":%d", conf.Server.Port),
-		func(fasthttpCtx *fasthttp.RequestCtx) {
-			fasthttpCtx.SetUserValue("ctx", ctx)
-			router.Handler(fasthttpCtx)
-		},
-	)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe(fmt.Sprintf(":%d", conf.Server.Port))
+	}()
 
-	if err != nil {
-		fmt.Println(err)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err = <-served:
+		if err != nil {
+			fmt.Println(err)
+		}
+	case received := <-signals:
+		fmt.Printf("Received %s, draining requests\n", received)
+		drain()
+
+		// Readiness fails from now on; keep accepting requests for a while so
+		// that whoever routes to this instance notices before it stops
+		// listening.
+		select {
+		case <-time.After(conf.Server.ReadinessGrace):
+		case received = <-signals:
+			fmt.Printf("Received %s, skipping readiness grace period\n", received)
+		}
+
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- server.Shutdown()
+		}()
+
+		select {
+		case err = <-stopped:
+			if err != nil {
+				fmt.Println(err)
+			}
+		case <-time.After(conf.Server.DrainTimeout):
+			fmt.Println("Drain timeout exceeded, cancelling remaining requests")
+			cancelRequests()
+		}
 	}
+
+	pool.Close()
+	fmt.Println("Server has been stopped")
 }
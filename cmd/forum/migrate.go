@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/migrations"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: forum migrate up | down [steps] | status"
+
+func RunMigrate(ctx context.Context, pool *pgxpool.Pool, dir string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	loaded, err := migrations.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	migrator := migrations.New(pool, loaded)
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps: %s", args[1])
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("nothing to revert")
+		}
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, state := range states {
+			status := "pending"
+			appliedAt := "-"
+
+			if state.Applied {
+				status = "applied"
+				appliedAt = state.AppliedAt.Format(time.RFC3339)
+			}
+			if state.Modified {
+				status = "modified"
+			}
+			if state.Missing {
+				status = "missing"
+			}
+
+			fmt.Fprintf(writer, "%04d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+		}
+
+		return writer.Flush()
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS votes;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS threads;
+DROP TABLE IF EXISTS forums_users;
+DROP TABLE IF EXISTS forums;
+DROP TABLE IF EXISTS users;
+
+DROP FUNCTION IF EXISTS forums_users__update();
+DROP FUNCTION IF EXISTS forums__count_posts();
+DROP FUNCTION IF EXISTS forums__count_threads();
+DROP FUNCTION IF EXISTS posts__set_path();
+DROP FUNCTION IF EXISTS threads__update_votes();
+DROP FUNCTION IF EXISTS threads__set_votes();
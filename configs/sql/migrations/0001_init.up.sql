@@ -70,6 +70,7 @@ CREATE OR REPLACE FUNCTION threads__set_votes() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS votes__on_insert__threads__set_votes ON votes;
 CREATE TRIGGER votes__on_insert__threads__set_votes
     AFTER INSERT ON votes
     FOR EACH ROW EXECUTE PROCEDURE threads__set_votes();
@@ -83,6 +84,7 @@ CREATE OR REPLACE FUNCTION threads__update_votes() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS votes__on_update__threads__update_votes ON votes;
 CREATE TRIGGER votes__on_update__threads__update_votes
     AFTER UPDATE ON votes
     FOR EACH ROW EXECUTE PROCEDURE threads__update_votes();
@@ -111,6 +113,7 @@ CREATE OR REPLACE FUNCTION posts__set_path() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE  plpgsql;
+DROP TRIGGER IF EXISTS posts__on_insert__threads__update_votes ON posts;
 CREATE TRIGGER posts__on_insert__threads__update_votes
     BEFORE INSERT ON posts
     FOR EACH ROW EXECUTE PROCEDURE posts__set_path();
@@ -124,6 +127,7 @@ CREATE OR REPLACE FUNCTION forums__count_threads() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS threads__on_insert__forums__count_threads ON threads;
 CREATE TRIGGER threads__on_insert__forums__count_threads
     AFTER INSERT ON threads
     FOR EACH ROW EXECUTE PROCEDURE forums__count_threads();
@@ -137,6 +141,7 @@ CREATE OR REPLACE FUNCTION forums__count_posts() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS posts__on_insert__forums__count_posts ON posts;
 CREATE TRIGGER posts__on_insert__forums__count_posts
     AFTER INSERT ON posts
     FOR EACH ROW EXECUTE PROCEDURE forums__count_posts();
@@ -160,9 +165,11 @@ CREATE OR REPLACE FUNCTION forums_users__update() RETURNS TRIGGER AS $$
         RETURN NEW;
     END;
 $$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS posts__on_insert__forums_users__update ON posts;
 CREATE TRIGGER posts__on_insert__forums_users__update
     AFTER INSERT ON posts
     FOR EACH ROW EXECUTE PROCEDURE forums_users__update();
+DROP TRIGGER IF EXISTS threads__on_insert__forums_users__update ON threads;
 CREATE TRIGGER threads__on_insert__forums_users__update
     AFTER INSERT ON threads
     FOR EACH ROW EXECUTE PROCEDURE forums_users__update();
@@ -185,5 +192,3 @@ CREATE INDEX IF NOT EXISTS post__forum__author ON Posts (forum, author);
 CREATE INDEX IF NOT EXISTS post__thread__id ON Posts (thread, id);
 
 CREATE INDEX IF NOT EXISTS forums_users__forum ON forums_users (forum, nickname);
-
-VACUUM ANALYZE;
@@ -0,0 +1,52 @@
+DROP INDEX IF EXISTS thread__forum__author;
+
+DROP TRIGGER IF EXISTS threads__on_delete__forums_users__cleanup ON threads;
+DROP TRIGGER IF EXISTS posts__on_delete__forums_users__cleanup ON posts;
+DROP FUNCTION IF EXISTS forums_users__cleanup();
+
+DROP TRIGGER IF EXISTS posts__on_delete__forums__uncount_posts ON posts;
+DROP FUNCTION IF EXISTS forums__uncount_posts();
+
+DROP TRIGGER IF EXISTS threads__on_delete__forums__uncount_threads ON threads;
+DROP FUNCTION IF EXISTS forums__uncount_threads();
+
+DROP TRIGGER IF EXISTS posts__on_delete__posts__delete_subtree ON posts;
+DROP FUNCTION IF EXISTS posts__delete_subtree();
+
+DROP TRIGGER IF EXISTS votes__on_delete__threads__revert_votes ON votes;
+DROP FUNCTION IF EXISTS threads__revert_votes();
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_thread_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_thread_fkey
+    FOREIGN KEY (thread) REFERENCES threads(id);
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_nickname_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_nickname_fkey
+    FOREIGN KEY (nickname) REFERENCES users(nickname);
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_thread_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_thread_fkey
+    FOREIGN KEY (thread) REFERENCES threads(id);
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_forum_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug);
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_author_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname);
+
+ALTER TABLE threads DROP CONSTRAINT IF EXISTS threads_forum_fkey;
+ALTER TABLE threads ADD CONSTRAINT threads_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug);
+ALTER TABLE threads DROP CONSTRAINT IF EXISTS threads_author_fkey;
+ALTER TABLE threads ADD CONSTRAINT threads_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname);
+
+ALTER TABLE forums_users DROP CONSTRAINT IF EXISTS forums_users_forum_fkey;
+ALTER TABLE forums_users ADD CONSTRAINT forums_users_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug);
+ALTER TABLE forums_users DROP CONSTRAINT IF EXISTS forums_users_nickname_fkey;
+ALTER TABLE forums_users ADD CONSTRAINT forums_users_nickname_fkey
+    FOREIGN KEY (nickname) REFERENCES users(nickname);
+
+ALTER TABLE forums DROP CONSTRAINT IF EXISTS forums_user_fkey;
+ALTER TABLE forums ADD CONSTRAINT forums_user_fkey
+    FOREIGN KEY ("user") REFERENCES users(nickname);
@@ -0,0 +1,118 @@
+ALTER TABLE forums DROP CONSTRAINT IF EXISTS forums_user_fkey;
+ALTER TABLE forums ADD CONSTRAINT forums_user_fkey
+    FOREIGN KEY ("user") REFERENCES users(nickname) ON DELETE CASCADE;
+
+ALTER TABLE forums_users DROP CONSTRAINT IF EXISTS forums_users_nickname_fkey;
+ALTER TABLE forums_users ADD CONSTRAINT forums_users_nickname_fkey
+    FOREIGN KEY (nickname) REFERENCES users(nickname) ON DELETE CASCADE;
+ALTER TABLE forums_users DROP CONSTRAINT IF EXISTS forums_users_forum_fkey;
+ALTER TABLE forums_users ADD CONSTRAINT forums_users_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug) ON DELETE CASCADE;
+
+ALTER TABLE threads DROP CONSTRAINT IF EXISTS threads_author_fkey;
+ALTER TABLE threads ADD CONSTRAINT threads_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname) ON DELETE CASCADE;
+ALTER TABLE threads DROP CONSTRAINT IF EXISTS threads_forum_fkey;
+ALTER TABLE threads ADD CONSTRAINT threads_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug) ON DELETE CASCADE;
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_author_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname) ON DELETE CASCADE;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_forum_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_forum_fkey
+    FOREIGN KEY (forum) REFERENCES forums(slug) ON DELETE CASCADE;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_thread_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_thread_fkey
+    FOREIGN KEY (thread) REFERENCES threads(id) ON DELETE CASCADE;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_nickname_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_nickname_fkey
+    FOREIGN KEY (nickname) REFERENCES users(nickname) ON DELETE CASCADE;
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_thread_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_thread_fkey
+    FOREIGN KEY (thread) REFERENCES threads(id) ON DELETE CASCADE;
+
+CREATE OR REPLACE FUNCTION threads__revert_votes() RETURNS TRIGGER AS $$
+    BEGIN
+        UPDATE threads t
+           SET votes = t.votes - d.voice
+          FROM (SELECT thread, SUM(voice) AS voice FROM deleted GROUP BY thread) d
+         WHERE t.id = d.thread;
+
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER votes__on_delete__threads__revert_votes
+    AFTER DELETE ON votes
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE threads__revert_votes();
+
+CREATE OR REPLACE FUNCTION posts__delete_subtree() RETURNS TRIGGER AS $$
+    BEGIN
+        DELETE FROM posts p
+         USING deleted d
+         WHERE p.thread = d.thread
+           AND p.path > d.path
+           AND p.path < d.path || 9223372036854775807::BIGINT;
+
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__on_delete__posts__delete_subtree
+    AFTER DELETE ON posts
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE posts__delete_subtree();
+
+CREATE OR REPLACE FUNCTION forums__uncount_threads() RETURNS TRIGGER AS $$
+    BEGIN
+        UPDATE forums f
+           SET threads = f.threads - d.threads
+          FROM (SELECT forum, COUNT(*) AS threads FROM deleted GROUP BY forum) d
+         WHERE f.slug = d.forum;
+
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER threads__on_delete__forums__uncount_threads
+    AFTER DELETE ON threads
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE forums__uncount_threads();
+
+CREATE OR REPLACE FUNCTION forums__uncount_posts() RETURNS TRIGGER AS $$
+    BEGIN
+        UPDATE forums f
+           SET posts = f.posts - d.posts
+          FROM (SELECT forum, COUNT(*) AS posts FROM deleted GROUP BY forum) d
+         WHERE f.slug = d.forum;
+
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__on_delete__forums__uncount_posts
+    AFTER DELETE ON posts
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE forums__uncount_posts();
+
+CREATE OR REPLACE FUNCTION forums_users__cleanup() RETURNS TRIGGER AS $$
+    BEGIN
+        DELETE FROM forums_users fu
+         USING (SELECT DISTINCT forum, author FROM deleted) d
+         WHERE fu.forum = d.forum
+           AND fu.nickname = d.author
+           AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.forum = d.forum AND t.author = d.author)
+           AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.forum = d.forum AND p.author = d.author);
+
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__on_delete__forums_users__cleanup
+    AFTER DELETE ON posts
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE forums_users__cleanup();
+CREATE TRIGGER threads__on_delete__forums_users__cleanup
+    AFTER DELETE ON threads
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE PROCEDURE forums_users__cleanup();
+
+CREATE INDEX IF NOT EXISTS thread__forum__author ON threads (forum, author);
@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_state_check;
+ALTER TABLE posts DROP COLUMN IF EXISTS state;
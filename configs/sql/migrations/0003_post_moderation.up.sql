@@ -0,0 +1,5 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS state TEXT NOT NULL DEFAULT 'visible';
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_state_check;
+ALTER TABLE posts ADD CONSTRAINT posts_state_check
+    CHECK (state IN ('visible', 'hidden', 'deleted_by_author', 'deleted_by_moderator'));
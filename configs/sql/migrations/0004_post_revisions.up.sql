@@ -0,0 +1,9 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS post_revisions (
+    post        BIGINT                      NOT NULL    REFERENCES posts(id) ON DELETE CASCADE,
+    number      INTEGER                     NOT NULL,
+    author      CITEXT COLLATE "C"          NOT NULL,
+    message     TEXT                        NOT NULL,
+    created     TIMESTAMP WITH TIME ZONE    DEFAULT now(),
+
+    CONSTRAINT post_revisions_pkey PRIMARY KEY (post, number)
+);
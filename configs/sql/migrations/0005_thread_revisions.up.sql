@@ -0,0 +1,13 @@
+ALTER TABLE threads ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 0;
+
+DROP TABLE IF EXISTS thread_revisions;
+CREATE UNLOGGED TABLE IF NOT EXISTS thread_revisions (
+    thread      BIGINT                      NOT NULL    REFERENCES threads(id) ON DELETE CASCADE,
+    number      INTEGER                     NOT NULL,
+    author      CITEXT COLLATE "C"          NOT NULL,
+    title       TEXT                        NOT NULL,
+    message     TEXT                        NOT NULL,
+    created     TIMESTAMP WITH TIME ZONE    DEFAULT now(),
+
+    CONSTRAINT thread_revisions_pkey PRIMARY KEY (thread, number)
+);
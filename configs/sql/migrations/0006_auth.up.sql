@@ -0,0 +1,13 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS credentials (
+    nickname        CITEXT COLLATE "C"          NOT NULL    PRIMARY KEY REFERENCES users(nickname) ON DELETE CASCADE,
+    password_hash   TEXT                        NOT NULL,
+    created         TIMESTAMP WITH TIME ZONE    DEFAULT now()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS tokens (
+    token_hash      TEXT                        NOT NULL    PRIMARY KEY,
+    nickname        CITEXT COLLATE "C"          NOT NULL    REFERENCES users(nickname) ON DELETE CASCADE,
+    expires         TIMESTAMP WITH TIME ZONE    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tokens__nickname ON tokens (nickname);
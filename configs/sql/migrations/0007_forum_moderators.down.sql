@@ -0,0 +1 @@
+DROP TABLE IF EXISTS forum_moderators;
@@ -0,0 +1,7 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_moderators (
+    forum       CITEXT                      NOT NULL    REFERENCES forums(slug) ON DELETE CASCADE,
+    nickname    CITEXT COLLATE "C"          NOT NULL    REFERENCES users(nickname) ON DELETE CASCADE,
+    granted     TIMESTAMP WITH TIME ZONE    DEFAULT now(),
+
+    CONSTRAINT forum_moderators_pkey PRIMARY KEY (forum, nickname)
+);
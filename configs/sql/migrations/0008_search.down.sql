@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS thread__search;
+DROP INDEX IF EXISTS post__search;
+
+ALTER TABLE threads DROP COLUMN IF EXISTS search;
+ALTER TABLE posts DROP COLUMN IF EXISTS search;
@@ -0,0 +1,10 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', message)) STORED;
+
+ALTER TABLE threads ADD COLUMN IF NOT EXISTS search TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', message), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS post__search ON posts USING gin (search);
+CREATE INDEX IF NOT EXISTS thread__search ON threads USING gin (search);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS post__thread__created__id;
+DROP INDEX IF EXISTS thread__forum__created__id;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS thread__forum__created__id ON threads (forum, created, id);
+CREATE INDEX IF NOT EXISTS post__thread__created__id ON posts (thread, created, id);
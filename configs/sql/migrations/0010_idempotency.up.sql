@@ -0,0 +1,13 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS idempotency_keys (
+    key             TEXT                        NOT NULL,
+    scope           TEXT                        NOT NULL,
+    fingerprint     TEXT                        NOT NULL,
+    status          INT                         NOT NULL    DEFAULT 0,
+    content_type    TEXT                        NOT NULL    DEFAULT '',
+    body            BYTEA,
+    created         TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT now(),
+
+    PRIMARY KEY (key, scope)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys__created ON idempotency_keys (created);
@@ -0,0 +1,7 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS import_ids (
+    kind        TEXT        NOT NULL,
+    legacy      BIGINT      NOT NULL,
+    id          BIGINT      NOT NULL,
+
+    PRIMARY KEY (kind, legacy)
+);
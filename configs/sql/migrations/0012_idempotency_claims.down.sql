@@ -0,0 +1,2 @@
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS headers;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS claim;
@@ -0,0 +1,2 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS claim TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
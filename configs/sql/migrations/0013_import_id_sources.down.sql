@@ -0,0 +1,6 @@
+DELETE FROM import_ids WHERE source <> 'default';
+
+ALTER TABLE import_ids DROP CONSTRAINT IF EXISTS import_ids_pkey;
+ALTER TABLE import_ids ADD CONSTRAINT import_ids_pkey PRIMARY KEY (kind, legacy);
+
+ALTER TABLE import_ids DROP COLUMN IF EXISTS source;
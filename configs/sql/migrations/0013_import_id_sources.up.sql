@@ -0,0 +1,5 @@
+ALTER TABLE import_ids ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE import_ids ALTER COLUMN source DROP DEFAULT;
+
+ALTER TABLE import_ids DROP CONSTRAINT IF EXISTS import_ids_pkey;
+ALTER TABLE import_ids ADD CONSTRAINT import_ids_pkey PRIMARY KEY (source, kind, legacy);
@@ -0,0 +1,11 @@
+ALTER TABLE forums DROP CONSTRAINT IF EXISTS forums_user_fkey;
+ALTER TABLE forums ADD CONSTRAINT forums_user_fkey
+    FOREIGN KEY ("user") REFERENCES users(nickname);
+
+ALTER TABLE threads DROP CONSTRAINT IF EXISTS threads_author_fkey;
+ALTER TABLE threads ADD CONSTRAINT threads_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname);
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_author_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_author_fkey
+    FOREIGN KEY (author) REFERENCES users(nickname);
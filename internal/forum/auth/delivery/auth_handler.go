@@ -0,0 +1,182 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+type AuthUseCase interface {
+	SetCredentials(ctx context.Context, credentials models.Credentials) (bool, error)
+	IssueToken(ctx context.Context, credentials models.Credentials) (models.Token, error)
+}
+
+type AuthHandler struct {
+	authUseCase AuthUseCase
+}
+
+func New(authUseCase AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+func (h *AuthHandler) SetCredentials(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var fromBody models.Credentials
+	if err := json.Unmarshal(rctx.PostBody(), &fromBody); err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	created, err := h.authUseCase.SetCredentials(ctx, fromBody)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "nickname and password are required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the user or an admin can set these credentials",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UniqueError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "credentials are already set for this user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusConflict)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	if created {
+		rctx.SetStatusCode(fasthttp.StatusCreated)
+	} else {
+		rctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	rctx.SetBody([]byte("{}"))
+}
+
+func (h *AuthHandler) IssueToken(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var fromBody models.Credentials
+	if err := json.Unmarshal(rctx.PostBody(), &fromBody); err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, err := h.authUseCase.IssueToken(ctx, fromBody)
+	if err != nil {
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid nickname or password",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusCreated)
+	rctx.SetBody(body)
+}
@@ -0,0 +1,6 @@
+package domain
+
+type Credentials struct {
+	Nickname     string
+	PasswordHash string
+}
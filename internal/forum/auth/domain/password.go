@@ -0,0 +1,10 @@
+package domain
+
+import "golang.org/x/crypto/bcrypt"
+
+// HashPassword hashes a password the way IssueToken expects to find it,
+// whether it is set at sign up or later on.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"time"
+)
+
+type Token struct {
+	Token    string
+	Nickname string
+	Expires  time.Time
+}
+
+func (token Token) ToModel() models.Token {
+	return models.Token{
+		Token:    token.Token,
+		Nickname: token.Nickname,
+		Expires:  &token.Expires,
+	}
+}
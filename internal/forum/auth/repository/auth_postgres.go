@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/rflban/parkmail-dbms/internal/forum/auth/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	queryCreateCredentials = `INSERT INTO credentials (nickname, password_hash)
+								SELECT nickname, $2 FROM users WHERE nickname = $1
+								RETURNING nickname;`
+	queryUpdateCredentials = `UPDATE credentials SET password_hash = $2 WHERE nickname = $1 RETURNING nickname;`
+	queryGetCredentials    = `SELECT nickname, password_hash FROM credentials WHERE nickname = $1;`
+	queryCreateToken       = `INSERT INTO tokens (token_hash, nickname, expires) VALUES ($1, $2, $3);`
+	queryGetTokenOwner     = `SELECT nickname FROM tokens WHERE token_hash = $1 AND expires > now();`
+	queryDeleteExpired     = `DELETE FROM tokens WHERE nickname = $1 AND expires <= now();`
+)
+
+type AuthRepositoryPostgres struct {
+	db *pgxhook.Pool
+}
+
+func New(db *pgxhook.Pool) *AuthRepositoryPostgres {
+	return &AuthRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *AuthRepositoryPostgres) CreateCredentials(ctx context.Context, credentials domain.Credentials) (domain.Credentials, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Auth",
+		"method": "CreateCredentials",
+	})
+
+	err := r.db.QueryRow(ctx, queryCreateCredentials, credentials.Nickname, credentials.PasswordHash).Scan(
+		&credentials.Nickname,
+	)
+
+	if err != nil {
+		log.Error(err.Error())
+
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return credentials, forumErrors.NewEntityNotExistsError("users")
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.SQLState() == "23505" {
+			return credentials, forumErrors.NewUniqueError(
+				pgErr.TableName,
+				pgErr.ColumnName,
+			)
+		}
+	}
+
+	return credentials, err
+}
+
+func (r *AuthRepositoryPostgres) UpdateCredentials(ctx context.Context, credentials domain.Credentials) (domain.Credentials, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Auth",
+		"method": "UpdateCredentials",
+	})
+
+	err := r.db.QueryRow(ctx, queryUpdateCredentials, credentials.Nickname, credentials.PasswordHash).Scan(
+		&credentials.Nickname,
+	)
+
+	if err != nil {
+		log.Error(err.Error())
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return credentials, forumErrors.NewEntityNotExistsError("credentials")
+		}
+	}
+
+	return credentials, err
+}
+
+func (r *AuthRepositoryPostgres) GetCredentials(ctx context.Context, nickname string) (domain.Credentials, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Auth",
+		"method": "GetCredentials",
+	})
+
+	var credentials domain.Credentials
+
+	err := r.db.QueryRow(ctx, queryGetCredentials, nickname).Scan(
+		&credentials.Nickname,
+		&credentials.PasswordHash,
+	)
+
+	if err != nil {
+		log.Error(err.Error())
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return credentials, forumErrors.NewEntityNotExistsError("credentials")
+		}
+	}
+
+	return credentials, err
+}
+
+func (r *AuthRepositoryPostgres) CreateToken(ctx context.Context, tokenHash string, nickname string, expires time.Time) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Auth",
+		"method": "CreateToken",
+	})
+
+	if _, err := r.db.Exec(ctx, queryDeleteExpired, nickname); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	_, err := r.db.Exec(ctx, queryCreateToken, tokenHash, nickname, expires)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *AuthRepositoryPostgres) GetTokenOwner(ctx context.Context, tokenHash string) (string, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Auth",
+		"method": "GetTokenOwner",
+	})
+
+	var nickname string
+
+	err := r.db.QueryRow(ctx, queryGetTokenOwner, tokenHash).Scan(&nickname)
+	if err != nil {
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return nickname, forumErrors.NewEntityNotExistsError("tokens")
+		}
+		log.Error(err.Error())
+	}
+
+	return nickname, err
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/rflban/parkmail-dbms/internal/forum/auth/domain"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+const tokenSize = 32
+
+type AuthRepository interface {
+	CreateCredentials(ctx context.Context, credentials domain.Credentials) (domain.Credentials, error)
+	UpdateCredentials(ctx context.Context, credentials domain.Credentials) (domain.Credentials, error)
+	GetCredentials(ctx context.Context, nickname string) (domain.Credentials, error)
+	CreateToken(ctx context.Context, tokenHash string, nickname string, expires time.Time) error
+	GetTokenOwner(ctx context.Context, tokenHash string) (string, error)
+}
+
+type AuthUseCaseImpl struct {
+	authRepo AuthRepository
+	tokenTTL time.Duration
+	admins   map[string]struct{}
+}
+
+func New(authRepo AuthRepository, tokenTTL time.Duration, admins []string) *AuthUseCaseImpl {
+	adminSet := make(map[string]struct{}, len(admins))
+	for _, admin := range admins {
+		adminSet[strings.ToLower(admin)] = struct{}{}
+	}
+
+	return &AuthUseCaseImpl{
+		authRepo: authRepo,
+		tokenTTL: tokenTTL,
+		admins:   adminSet,
+	}
+}
+
+func (u *AuthUseCaseImpl) SetCredentials(ctx context.Context, credentials models.Credentials) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.SetCredentials")
+	defer tracing.End(span, &err)
+
+	if credentials.Nickname == "" {
+		return false, forumErrors.NewInvalidArgumentError("nickname", credentials.Nickname)
+	}
+	if credentials.Password == "" {
+		return false, forumErrors.NewInvalidArgumentError("password", "")
+	}
+
+	// Users get their first password at sign up. Past that, only the user
+	// and admins can set one, which is how admins give a password to users
+	// created before auth existed.
+	if _, ok := identity.Caller(ctx); !ok {
+		return false, forumErrors.NewUnauthorizedError("authentication required")
+	}
+	if !identity.Is(ctx, credentials.Nickname) && !identity.IsAdmin(ctx) {
+		return false, forumErrors.NewForbiddenError("change credentials of another user")
+	}
+
+	hash, err := domain.HashPassword(credentials.Password)
+	if err != nil {
+		return false, err
+	}
+
+	toSet := domain.Credentials{
+		Nickname:     credentials.Nickname,
+		PasswordHash: hash,
+	}
+
+	_, err = u.authRepo.GetCredentials(ctx, credentials.Nickname)
+	if _, notExists := err.(forumErrors.EntityNotExistsError); notExists {
+		_, err = u.authRepo.CreateCredentials(ctx, toSet)
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = u.authRepo.UpdateCredentials(ctx, toSet)
+	return false, err
+}
+
+func (u *AuthUseCaseImpl) IssueToken(ctx context.Context, credentials models.Credentials) (_ models.Token, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.IssueToken")
+	defer tracing.End(span, &err)
+
+	stored, err := u.authRepo.GetCredentials(ctx, credentials.Nickname)
+	if _, notExists := err.(forumErrors.EntityNotExistsError); notExists {
+		return models.Token{}, forumErrors.NewUnauthorizedError("invalid nickname or password")
+	}
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte(credentials.Password)) != nil {
+		return models.Token{}, forumErrors.NewUnauthorizedError("invalid nickname or password")
+	}
+
+	raw := make([]byte, tokenSize)
+	if _, err := rand.Read(raw); err != nil {
+		return models.Token{}, err
+	}
+
+	token := domain.Token{
+		Token:    hex.EncodeToString(raw),
+		Nickname: stored.Nickname,
+		Expires:  time.Now().Add(u.tokenTTL),
+	}
+
+	err = u.authRepo.CreateToken(ctx, hashToken(token.Token), token.Nickname, token.Expires)
+	return token.ToModel(), err
+}
+
+func (u *AuthUseCaseImpl) Resolve(ctx context.Context, token string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.Resolve")
+	defer tracing.End(span, &err)
+
+	nickname, err := u.authRepo.GetTokenOwner(ctx, hashToken(token))
+	if _, notExists := err.(forumErrors.EntityNotExistsError); notExists {
+		return "", forumErrors.NewUnauthorizedError("invalid or expired token")
+	}
+	return nickname, err
+}
+
+func (u *AuthUseCaseImpl) IsAdmin(nickname string) bool {
+	_, ok := u.admins[strings.ToLower(nickname)]
+	return ok
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"io"
+)
+
+type ExportUseCase interface {
+	Export(ctx context.Context, slug string, format string) (func(w io.Writer) error, error)
+}
+
+type ExportHandler struct {
+	exportUseCase ExportUseCase
+}
+
+func New(exportUseCase ExportUseCase) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+func (h *ExportHandler) Export(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slug, ok := rctx.UserValue("slug").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	format := string(rctx.QueryArgs().Peek("format"))
+	if format == "" {
+		format = bundle.FormatNDJSON
+	}
+
+	write, err := h.exportUseCase.Export(ctx, slug, format)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "format must be ndjson or tar",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only moderators of the forum can export it",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetContentType(bundle.ContentType(format))
+	rctx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", slug+"."+format))
+
+	// The status is already sent once streaming starts, so a failure half way
+	// can only be logged; the client sees a truncated body.
+	rctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			log.Error(err.Error())
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	queryGetForum = `SELECT title, "user", slug FROM forums WHERE slug = $1;`
+	// Participants are the forum owner, everyone who wrote in it and
+	// everyone who voted in its threads.
+	queryGetUsers = `SELECT nickname, fullname, about, email
+					   FROM users
+					  WHERE nickname IN (
+								SELECT "user" FROM forums WHERE slug = $1
+								UNION
+								SELECT nickname FROM forums_users WHERE forum = $1
+								UNION
+								SELECT v.nickname FROM votes v JOIN threads t ON t.id = v.thread WHERE t.forum = $1
+							)
+					  ORDER BY nickname;`
+	queryGetThreads = `SELECT id, title, author, forum, message, votes, slug, created, revision
+						 FROM threads
+						WHERE forum = $1
+						ORDER BY created, id;`
+	queryGetPosts = `SELECT id, parent, author, message, is_edited, forum, thread, created, state
+					   FROM posts
+					  WHERE thread IN (SELECT id FROM threads WHERE forum = $1)
+					  ORDER BY thread, path;`
+	queryGetVotes = `SELECT v.nickname, v.voice, v.thread
+					   FROM votes v
+					   JOIN threads t ON t.id = v.thread
+					  WHERE t.forum = $1
+					  ORDER BY v.thread, v.nickname;`
+)
+
+type ExportRepoPostgres struct {
+	db *pgxhook.Pool
+}
+
+func New(db *pgxhook.Pool) *ExportRepoPostgres {
+	return &ExportRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *ExportRepoPostgres) GetForum(ctx context.Context, slug string) (models.Forum, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Export",
+		"method": "GetForum",
+	})
+
+	forum := models.Forum{}
+	err := r.db.QueryRow(ctx, queryGetForum, slug).Scan(&forum.Title, &forum.User, &forum.Slug)
+	if err != nil {
+		log.Error(err.Error())
+
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return forum, forumErrors.NewEntityNotExistsError("forums")
+		}
+	}
+
+	return forum, err
+}
+
+// Export reads the forum and everything that belongs to it from a single
+// snapshot and hands the records to emit in the order the import needs.
+func (r *ExportRepoPostgres) Export(ctx context.Context, slug string, emit func(kind bundle.Kind, item interface{}) error) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Export",
+		"method": "Export",
+	})
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	forum := models.Forum{}
+	err = tx.QueryRow(ctx, queryGetForum, slug).Scan(&forum.Title, &forum.User, &forum.Slug)
+	if err != nil {
+		log.Error(err.Error())
+
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return forumErrors.NewEntityNotExistsError("forums")
+		}
+		return err
+	}
+
+	steps := []struct {
+		kind  bundle.Kind
+		query string
+		scan  func(rows pgx.Rows) (interface{}, error)
+	}{
+		{bundle.Users, queryGetUsers, scanUser},
+		{bundle.Threads, queryGetThreads, scanThread},
+		{bundle.Posts, queryGetPosts, scanPost},
+		{bundle.Votes, queryGetVotes, scanVote},
+	}
+
+	for _, step := range steps {
+		if err = r.stream(ctx, tx, step.kind, step.query, forum.Slug, step.scan, emit); err != nil {
+			log.Error(err.Error())
+			return err
+		}
+
+		if step.kind == bundle.Users {
+			if err = emit(bundle.Forums, forum); err != nil {
+				log.Error(err.Error())
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *ExportRepoPostgres) stream(
+	ctx context.Context,
+	tx pgx.Tx,
+	kind bundle.Kind,
+	query string,
+	slug string,
+	scan func(rows pgx.Rows) (interface{}, error),
+	emit func(kind bundle.Kind, item interface{}) error,
+) error {
+	rows, err := tx.Query(ctx, query, slug)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return err
+		}
+
+		if err = emit(kind, item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanUser(rows pgx.Rows) (interface{}, error) {
+	var nickname string
+	user := models.User{}
+
+	err := rows.Scan(&nickname, &user.Fullname, &user.About, &user.Email)
+	user.Nickname = &nickname
+
+	return user, err
+}
+
+func scanThread(rows pgx.Rows) (interface{}, error) {
+	var (
+		id       int64
+		forum    string
+		votes    int64
+		created  time.Time
+		revision int32
+	)
+	thread := models.Thread{}
+
+	err := rows.Scan(&id, &thread.Title, &thread.Author, &forum, &thread.Message, &votes, &thread.Slug, &created, &revision)
+
+	threadId, threadVotes := int32(id), int32(votes)
+	thread.Id = &threadId
+	thread.Forum = &forum
+	thread.Votes = &threadVotes
+	thread.Created = &created
+	thread.Revision = &revision
+
+	return thread, err
+}
+
+func scanPost(rows pgx.Rows) (interface{}, error) {
+	var (
+		id       int64
+		parent   int64
+		isEdited bool
+		forum    string
+		thread   int64
+		created  time.Time
+		state    string
+	)
+	post := models.Post{}
+
+	err := rows.Scan(&id, &parent, &post.Author, &post.Message, &isEdited, &forum, &thread, &created, &state)
+
+	postThread := int32(thread)
+	post.Id = &id
+	post.Parent = &parent
+	post.IsEdited = &isEdited
+	post.Forum = &forum
+	post.Thread = &postThread
+	post.Created = &created
+	post.State = &state
+
+	return post, err
+}
+
+func scanVote(rows pgx.Rows) (interface{}, error) {
+	vote := bundle.Vote{}
+	err := rows.Scan(&vote.Nickname, &vote.Voice, &vote.Thread)
+	return vote, err
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"io"
+)
+
+type ExportRepository interface {
+	GetForum(ctx context.Context, slug string) (models.Forum, error)
+	Export(ctx context.Context, slug string, emit func(kind bundle.Kind, item interface{}) error) error
+}
+
+type ExportUseCaseImpl struct {
+	exportRepo ExportRepository
+	checker    identity.ModerationChecker
+}
+
+func New(exportRepo ExportRepository, checker identity.ModerationChecker) *ExportUseCaseImpl {
+	return &ExportUseCaseImpl{
+		exportRepo: exportRepo,
+		checker:    checker,
+	}
+}
+
+// Export checks everything that can fail before the first byte of an
+// export is written and returns the function that streams it. An export
+// holds hidden and deleted posts as they are, so only moderators of the
+// forum may take one. The span of the export lasts until the returned
+// function is done, so the function must be called.
+func (u *ExportUseCaseImpl) Export(ctx context.Context, slug string, format string) (_ func(w io.Writer) error, err error) {
+	ctx, span := tracing.Start(ctx, "ExportUseCase.Export")
+	defer func() {
+		if err != nil {
+			tracing.End(span, &err)
+		}
+	}()
+
+	if !bundle.IsValidFormat(format) {
+		return nil, forumErrors.NewInvalidArgumentError("format", format)
+	}
+
+	forum, err := u.exportRepo.GetForum(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	canModerate, err := identity.CanModerate(ctx, u.checker, forum.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerate {
+		return nil, forumErrors.NewForbiddenError("export the forum")
+	}
+
+	return func(w io.Writer) (err error) {
+		defer tracing.End(span, &err)
+
+		writer, err := bundle.NewWriter(format, w)
+		if err != nil {
+			return err
+		}
+
+		if err = u.exportRepo.Export(ctx, forum.Slug, writer.Write); err != nil {
+			return err
+		}
+
+		return writer.Close()
+	}, nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/envelope"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -14,8 +17,12 @@ import (
 type ForumUseCase interface {
 	Create(ctx context.Context, forum models.Forum) (models.Forum, error)
 	GetBySlug(ctx context.Context, slug string) (models.Forum, error)
-	GetUsersBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) (models.Users, error)
-	GetThreadsBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) (models.Threads, error)
+	Delete(ctx context.Context, slug string) error
+	GetUsersBySlug(ctx context.Context, slug string, since string, after string, limit uint64, desc *bool, withTotal bool) (models.UsersPage, error)
+	GetThreadsBySlug(ctx context.Context, slug string, since string, after string, limit uint64, desc *bool, withTotal bool) (models.ThreadsPage, error)
+	GetModerators(ctx context.Context, slug string) (models.Users, error)
+	AddModerator(ctx context.Context, slug string, nickname string) (models.Users, error)
+	RemoveModerator(ctx context.Context, slug string, nickname string) error
 }
 
 type ThreadUseCase interface {
@@ -44,7 +51,8 @@ func (h *ForumHandler) Create(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -56,7 +64,8 @@ func (h *ForumHandler) Create(rctx *fasthttp.RequestCtx) {
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread not found",
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -69,7 +78,8 @@ func (h *ForumHandler) Create(rctx *fasthttp.RequestCtx) {
 
 			if err != nil {
 				body, _ = json.Marshal(models.Error{
-					Message: "internal server error",
+					Message:   "internal server error",
+					RequestId: middlewares.RequestIdFrom(ctx),
 				})
 			}
 
@@ -78,8 +88,20 @@ func (h *ForumHandler) Create(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not create a forum on behalf of another user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -92,7 +114,8 @@ func (h *ForumHandler) Create(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -113,7 +136,8 @@ func (h *ForumHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug",
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -125,7 +149,8 @@ func (h *ForumHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "forum not found",
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -134,7 +159,8 @@ func (h *ForumHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -147,7 +173,8 @@ func (h *ForumHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -168,7 +195,8 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug",
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -181,7 +209,8 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -194,7 +223,8 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread or author not found",
+				Message:   "thread or author not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -207,7 +237,8 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 
 			if err != nil {
 				body, _ = json.Marshal(models.Error{
-					Message: "internal server error",
+					Message:   "internal server error",
+					RequestId: middlewares.RequestIdFrom(ctx),
 				})
 			}
 
@@ -216,8 +247,20 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not create a thread on behalf of another user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -230,7 +273,8 @@ func (h *ForumHandler) CreateThread(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -251,7 +295,8 @@ func (h *ForumHandler) GetUsers(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug",
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -265,13 +310,32 @@ func (h *ForumHandler) GetUsers(rctx *fasthttp.RequestCtx) {
 
 	since := string(sinceRaw)
 	limit, _ := strconv.ParseUint(string(limitRaw), 10, 64)
-	desc := string(descRaw) == "true"
+	after := string(rctx.QueryArgs().Peek("cursor"))
+
+	var desc *bool
+	if rctx.QueryArgs().Has("desc") {
+		descParsed := string(descRaw) == "true"
+		desc = &descParsed
+	}
+
+	wrap := envelope.Requested(rctx)
 
-	obtained, err := h.forumUseCase.GetUsersBySlug(ctx, slug, since, limit, desc)
+	page, err := h.forumUseCase.GetUsersBySlug(ctx, slug, since, after, limit, desc, wrap)
 	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid cursor or desc",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "forum not found",
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -280,7 +344,8 @@ func (h *ForumHandler) GetUsers(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -288,12 +353,18 @@ func (h *ForumHandler) GetUsers(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	body, err := json.Marshal(obtained)
+	var body []byte
+	if wrap {
+		body, err = json.Marshal(page)
+	} else {
+		body, err = json.Marshal(page.Items)
+	}
 	if err != nil {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -301,6 +372,9 @@ func (h *ForumHandler) GetUsers(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if page.NextCursor != nil {
+		rctx.Response.Header.Set(cursor.NextHeader, *page.NextCursor)
+	}
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
@@ -314,7 +388,8 @@ func (h *ForumHandler) GetThreads(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug",
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -326,18 +401,172 @@ func (h *ForumHandler) GetThreads(rctx *fasthttp.RequestCtx) {
 	limitRaw := rctx.QueryArgs().Peek("limit")
 	descRaw := rctx.QueryArgs().Peek("desc")
 
-	desc := string(descRaw) == "true"
 	since := string(sinceRaw)
+	after := string(rctx.QueryArgs().Peek("cursor"))
+
+	var desc *bool
+	if rctx.QueryArgs().Has("desc") {
+		descParsed := string(descRaw) == "true"
+		desc = &descParsed
+	}
 	limit, err := strconv.ParseUint(string(limitRaw), 10, 64)
 	if err != nil {
 		limit = 0
 	}
 
-	obtained, err := h.forumUseCase.GetThreadsBySlug(ctx, slug, since, limit, desc)
+	wrap := envelope.Requested(rctx)
+
+	page, err := h.forumUseCase.GetThreadsBySlug(ctx, slug, since, after, limit, desc, wrap)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid cursor or desc",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	var body []byte
+	if wrap {
+		body, err = json.Marshal(page)
+	} else {
+		body, err = json.Marshal(page.Items)
+	}
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	if page.NextCursor != nil {
+		rctx.Response.Header.Set(cursor.NextHeader, *page.NextCursor)
+	}
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *ForumHandler) Delete(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slug, ok := rctx.UserValue("slug").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	err := h.forumUseCase.Delete(ctx, slug)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the forum owner can delete it",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Del(fasthttp.HeaderContentType)
+	rctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func (h *ForumHandler) GetModerators(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slug, ok := rctx.UserValue("slug").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, err := h.forumUseCase.GetModerators(ctx, slug)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "forum not found",
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -346,7 +575,8 @@ func (h *ForumHandler) GetThreads(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -359,7 +589,8 @@ func (h *ForumHandler) GetThreads(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -370,3 +601,179 @@ func (h *ForumHandler) GetThreads(rctx *fasthttp.RequestCtx) {
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
+
+func (h *ForumHandler) AddModerator(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slug, ok := rctx.UserValue("slug").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	nickname, ok := rctx.UserValue("nickname").(string)
+	if !ok {
+		log.Errorf("Can't parse nickname: %v", rctx.UserValue("nickname"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, err := h.forumUseCase.AddModerator(ctx, slug, nickname)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum or user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the forum owner can manage moderators",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusCreated)
+	rctx.SetBody(body)
+}
+
+func (h *ForumHandler) RemoveModerator(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slug, ok := rctx.UserValue("slug").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	nickname, ok := rctx.UserValue("nickname").(string)
+	if !ok {
+		log.Errorf("Can't parse nickname: %v", rctx.UserValue("nickname"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	err := h.forumUseCase.RemoveModerator(ctx, slug, nickname)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum or moderator not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the forum owner can manage moderators",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Del(fasthttp.HeaderContentType)
+	rctx.SetStatusCode(fasthttp.StatusNoContent)
+}
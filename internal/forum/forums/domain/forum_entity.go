@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/rflban/parkmail-dbms/pkg/forum/models"
+import (
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"strconv"
+)
 
 type Forum struct {
 	Id      int64
@@ -11,6 +15,18 @@ type Forum struct {
 	Threads int32
 }
 
+// ETag covers everything a forum renders to, its counters included.
+func (forum Forum) ETag() string {
+	return etag.OfFields(
+		"forum",
+		forum.Slug,
+		forum.Title,
+		forum.User,
+		strconv.FormatInt(forum.Posts, 10),
+		strconv.FormatInt(int64(forum.Threads), 10),
+	)
+}
+
 func (forum Forum) ToModel() models.Forum {
 	return models.Forum{
 		Title:   forum.Title,
@@ -6,25 +6,39 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/forums/domain"
 	threadsDomain "github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	usersDomain "github.com/rflban/parkmail-dbms/internal/forum/users/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	queryCreate    = `INSERT INTO forums (title, "user", slug, posts, threads) VALUES ($1, $2, $3, $4, $5) RETURNING id, title, "user", slug, posts, threads;`
-	queryGetBySlug = `SELECT id, title, "user", slug, posts, threads FROM forums WHERE slug = $1;`
+	queryCreate     = `INSERT INTO forums (title, "user", slug, posts, threads) VALUES ($1, $2, $3, $4, $5) RETURNING id, title, "user", slug, posts, threads;`
+	queryGetBySlug  = `SELECT id, title, "user", slug, posts, threads FROM forums WHERE slug = $1;`
+	queryDelete     = `DELETE FROM forums WHERE slug = $1;`
+	queryCountUsers = `SELECT COUNT(*) FROM forums_users WHERE forum = $1;`
+
+	queryCanModerate = `SELECT EXISTS (SELECT 1 FROM forums WHERE slug = $1 AND "user" = $2)
+							OR EXISTS (SELECT 1 FROM forum_moderators WHERE forum = $1 AND nickname = $2);`
+	queryAddModerator = `INSERT INTO forum_moderators (forum, nickname)
+							SELECT f.slug, u.nickname FROM forums f, users u WHERE f.slug = $1 AND u.nickname = $2
+							ON CONFLICT (forum, nickname) DO UPDATE SET granted = forum_moderators.granted
+							RETURNING nickname;`
+	queryRemoveModerator = `DELETE FROM forum_moderators WHERE forum = $1 AND nickname = $2;`
+	queryGetModerators   = `SELECT u.id, u.nickname, u.fullname, u.about, u.email
+							FROM forum_moderators m JOIN users u ON u.nickname = m.nickname
+							WHERE m.forum = $1
+							ORDER BY u.nickname;`
 )
 
 type ForumRepositoryPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *ForumRepositoryPostgres {
+func New(db *pgxhook.Pool) *ForumRepositoryPostgres {
 	return &ForumRepositoryPostgres{
 		db: db,
 	}
@@ -109,6 +123,113 @@ func (r *ForumRepositoryPostgres) GetBySlug(ctx context.Context, slug string) (d
 	return forum, err
 }
 
+func (r *ForumRepositoryPostgres) Delete(ctx context.Context, slug string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "Delete",
+	})
+
+	tag, err := r.db.Exec(ctx, queryDelete, slug)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("forums")
+	}
+
+	return nil
+}
+
+func (r *ForumRepositoryPostgres) CanModerate(ctx context.Context, slug string, nickname string) (bool, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "CanModerate",
+	})
+
+	var canModerate bool
+
+	err := r.db.QueryRow(ctx, queryCanModerate, slug, nickname).Scan(&canModerate)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return canModerate, err
+}
+
+func (r *ForumRepositoryPostgres) AddModerator(ctx context.Context, slug string, nickname string) (string, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "AddModerator",
+	})
+
+	var added string
+
+	err := r.db.QueryRow(ctx, queryAddModerator, slug, nickname).Scan(&added)
+	if err != nil {
+		log.Error(err.Error())
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return added, forumErrors.NewEntityNotExistsError("forums or users")
+		}
+	}
+
+	return added, err
+}
+
+func (r *ForumRepositoryPostgres) RemoveModerator(ctx context.Context, slug string, nickname string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "RemoveModerator",
+	})
+
+	tag, err := r.db.Exec(ctx, queryRemoveModerator, slug, nickname)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("forum_moderators")
+	}
+
+	return nil
+}
+
+func (r *ForumRepositoryPostgres) GetModerators(ctx context.Context, slug string) ([]usersDomain.User, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "GetModerators",
+	})
+
+	rows, err := r.db.Query(ctx, queryGetModerators, slug)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]usersDomain.User, 0)
+	for rows.Next() {
+		var user usersDomain.User
+
+		err := rows.Scan(
+			&user.Id,
+			&user.Nickname,
+			&user.Fullname,
+			&user.About,
+			&user.Email,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
 func (r *ForumRepositoryPostgres) GetUsersBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) ([]usersDomain.User, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Forum",
@@ -171,18 +292,39 @@ func (r *ForumRepositoryPostgres) GetUsersBySlug(ctx context.Context, slug strin
 	return users, nil
 }
 
-func (r *ForumRepositoryPostgres) GetThreadsBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) ([]threadsDomain.Thread, error) {
+func (r *ForumRepositoryPostgres) CountUsersBySlug(ctx context.Context, slug string) (int64, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Forum",
+		"method": "CountUsersBySlug",
+	})
+
+	var count int64
+	err := r.db.QueryRow(ctx, queryCountUsers, slug).Scan(&count)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return count, err
+}
+
+func (r *ForumRepositoryPostgres) GetThreadsBySlug(ctx context.Context, slug string, since string, after *threadsDomain.Cursor, limit uint64, desc bool) ([]threadsDomain.Thread, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Forum",
 		"method": "GetThreadsBySlug",
 	})
 
 	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id, title, author, forum, message, votes, slug, created").
+		Select("id, title, author, forum, message, votes, slug, created, revision").
 		From("threads").
 		Where("forum = ?", slug)
 
-	if since != "" {
+	if after != nil {
+		if desc {
+			queryBuilder = queryBuilder.Where("(created, id) < (?, ?)", after.Created, after.Id)
+		} else {
+			queryBuilder = queryBuilder.Where("(created, id) > (?, ?)", after.Created, after.Id)
+		}
+	} else if since != "" {
 		if desc {
 			queryBuilder = queryBuilder.Where("created <= ?", since)
 		} else {
@@ -191,9 +333,9 @@ func (r *ForumRepositoryPostgres) GetThreadsBySlug(ctx context.Context, slug str
 	}
 
 	if desc {
-		queryBuilder = queryBuilder.OrderBy("created DESC")
+		queryBuilder = queryBuilder.OrderBy("created DESC, id DESC")
 	} else {
-		queryBuilder = queryBuilder.OrderBy("created ASC")
+		queryBuilder = queryBuilder.OrderBy("created ASC, id ASC")
 	}
 
 	if limit > 0 {
@@ -229,6 +371,7 @@ func (r *ForumRepositoryPostgres) GetThreadsBySlug(ctx context.Context, slug str
 			&thread.Votes,
 			&fetchedSlug,
 			&thread.Created,
+			&thread.Revision,
 		)
 		if err != nil {
 			log.Error(err.Error())
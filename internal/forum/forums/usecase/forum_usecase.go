@@ -5,15 +5,25 @@ import (
 	"github.com/rflban/parkmail-dbms/internal/forum/forums/domain"
 	threadsDomain "github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	usersDomain "github.com/rflban/parkmail-dbms/internal/forum/users/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"strconv"
 )
 
 type ForumRepository interface {
 	Create(ctx context.Context, forum domain.Forum) (domain.Forum, error)
 	GetBySlug(ctx context.Context, slug string) (domain.Forum, error)
+	Delete(ctx context.Context, slug string) error
+	CanModerate(ctx context.Context, slug string, nickname string) (bool, error)
+	AddModerator(ctx context.Context, slug string, nickname string) (string, error)
+	RemoveModerator(ctx context.Context, slug string, nickname string) error
+	GetModerators(ctx context.Context, slug string) ([]usersDomain.User, error)
 	GetUsersBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) ([]usersDomain.User, error)
-	GetThreadsBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) ([]threadsDomain.Thread, error)
+	CountUsersBySlug(ctx context.Context, slug string) (int64, error)
+	GetThreadsBySlug(ctx context.Context, slug string, since string, after *threadsDomain.Cursor, limit uint64, desc bool) ([]threadsDomain.Thread, error)
 }
 
 type ForumUseCaseImpl struct {
@@ -26,7 +36,14 @@ func New(forumRepo ForumRepository) *ForumUseCaseImpl {
 	}
 }
 
-func (u *ForumUseCaseImpl) Create(ctx context.Context, forum models.Forum) (models.Forum, error) {
+func (u *ForumUseCaseImpl) Create(ctx context.Context, forum models.Forum) (_ models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.Create")
+	defer tracing.End(span, &err)
+
+	if err := identity.ActAs(ctx, forum.User); err != nil {
+		return models.Forum{}, err
+	}
+
 	created, err := u.forumRepo.Create(ctx, domain.FromModel(forum, nil))
 
 	if err == nil {
@@ -46,19 +63,40 @@ func (u *ForumUseCaseImpl) Create(ctx context.Context, forum models.Forum) (mode
 	return existing.ToModel(), conflict
 }
 
-func (u *ForumUseCaseImpl) GetBySlug(ctx context.Context, slug string) (models.Forum, error) {
+func (u *ForumUseCaseImpl) GetBySlug(ctx context.Context, slug string) (_ models.Forum, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.GetBySlug")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.forumRepo.GetBySlug(ctx, slug)
 	return obtained.ToModel(), err
 }
 
-func (u *ForumUseCaseImpl) GetUsersBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) (models.Users, error) {
-	_, err := u.forumRepo.GetBySlug(ctx, slug)
+func (u *ForumUseCaseImpl) Delete(ctx context.Context, slug string) (err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.Delete")
+	defer tracing.End(span, &err)
+
+	forum, err := u.forumRepo.GetBySlug(ctx, slug)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if err := u.checkOwner(ctx, forum); err != nil {
+		return err
 	}
 
-	obtained, err := u.forumRepo.GetUsersBySlug(ctx, slug, since, limit, desc)
+	return u.forumRepo.Delete(ctx, slug)
+}
+
+func (u *ForumUseCaseImpl) GetModerators(ctx context.Context, slug string) (_ models.Users, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.GetModerators")
+	defer tracing.End(span, &err)
 
+	_, err = u.forumRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	obtained, err := u.forumRepo.GetModerators(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
@@ -68,19 +106,136 @@ func (u *ForumUseCaseImpl) GetUsersBySlug(ctx context.Context, slug string, sinc
 		users = append(users, user.ToModel())
 	}
 
-	return users, err
+	return users, nil
 }
 
-func (u *ForumUseCaseImpl) GetThreadsBySlug(ctx context.Context, slug string, since string, limit uint64, desc bool) (models.Threads, error) {
-	_, err := u.forumRepo.GetBySlug(ctx, slug)
+func (u *ForumUseCaseImpl) AddModerator(ctx context.Context, slug string, nickname string) (_ models.Users, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.AddModerator")
+	defer tracing.End(span, &err)
+
+	forum, err := u.forumRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
 
-	obtained, err := u.forumRepo.GetThreadsBySlug(ctx, slug, since, limit, desc)
+	if err := u.checkOwner(ctx, forum); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.forumRepo.AddModerator(ctx, slug, nickname); err != nil {
+		return nil, err
+	}
+
+	return u.GetModerators(ctx, slug)
+}
 
+func (u *ForumUseCaseImpl) RemoveModerator(ctx context.Context, slug string, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.RemoveModerator")
+	defer tracing.End(span, &err)
+
+	forum, err := u.forumRepo.GetBySlug(ctx, slug)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if err := u.checkOwner(ctx, forum); err != nil {
+		return err
+	}
+
+	return u.forumRepo.RemoveModerator(ctx, slug, nickname)
+}
+
+func (u *ForumUseCaseImpl) GetUsersBySlug(ctx context.Context, slug string, since string, after string, limit uint64, desc *bool, withTotal bool) (_ models.UsersPage, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.GetUsersBySlug")
+	defer tracing.End(span, &err)
+
+	page := models.UsersPage{}
+
+	descending := desc != nil && *desc
+
+	if after != "" {
+		position := usersDomain.Cursor{}
+		if err := cursor.Decode(after, &position); err != nil {
+			return page, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		if desc != nil && *desc != position.Desc {
+			return page, forumErrors.NewInvalidArgumentError("desc", strconv.FormatBool(*desc))
+		}
+		since, descending = position.Nickname, position.Desc
+	}
+
+	_, err = u.forumRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return page, err
+	}
+
+	obtained, err := u.forumRepo.GetUsersBySlug(ctx, slug, since, cursor.FetchLimit(limit), descending)
+
+	if err != nil {
+		return page, err
+	}
+
+	if cursor.HasMore(len(obtained), limit) {
+		obtained = obtained[:limit]
+		next, err := cursor.Encode(obtained[limit-1].Cursor(descending))
+		if err != nil {
+			return page, err
+		}
+		page.HasMore, page.NextCursor = true, &next
+	}
+
+	users := make(models.Users, 0, len(obtained))
+	for _, user := range obtained {
+		users = append(users, user.ToModel())
+	}
+
+	page.Items = users
+
+	if withTotal {
+		page.Total, err = u.forumRepo.CountUsersBySlug(ctx, slug)
+	}
+
+	return page, err
+}
+
+func (u *ForumUseCaseImpl) GetThreadsBySlug(ctx context.Context, slug string, since string, after string, limit uint64, desc *bool, withTotal bool) (_ models.ThreadsPage, err error) {
+	ctx, span := tracing.Start(ctx, "ForumUseCase.GetThreadsBySlug")
+	defer tracing.End(span, &err)
+
+	page := models.ThreadsPage{}
+
+	descending := desc != nil && *desc
+
+	var position *threadsDomain.Cursor
+	if after != "" {
+		position = &threadsDomain.Cursor{}
+		if err := cursor.Decode(after, position); err != nil {
+			return page, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		if desc != nil && *desc != position.Desc {
+			return page, forumErrors.NewInvalidArgumentError("desc", strconv.FormatBool(*desc))
+		}
+		descending = position.Desc
+	}
+
+	forum, err := u.forumRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return page, err
+	}
+
+	obtained, err := u.forumRepo.GetThreadsBySlug(ctx, slug, since, position, cursor.FetchLimit(limit), descending)
+
+	if err != nil {
+		return page, err
+	}
+
+	if cursor.HasMore(len(obtained), limit) {
+		obtained = obtained[:limit]
+		next, err := cursor.Encode(obtained[limit-1].Cursor(descending))
+		if err != nil {
+			return page, err
+		}
+		page.HasMore, page.NextCursor = true, &next
 	}
 
 	threads := make(models.Threads, 0, len(obtained))
@@ -88,5 +243,15 @@ func (u *ForumUseCaseImpl) GetThreadsBySlug(ctx context.Context, slug string, si
 		threads = append(threads, thread.ToModel())
 	}
 
-	return threads, err
+	page.Items = threads
+
+	if withTotal {
+		page.Total = int64(forum.Threads)
+	}
+
+	return page, err
+}
+
+func (u *ForumUseCaseImpl) checkOwner(ctx context.Context, forum domain.Forum) error {
+	return identity.Own(ctx, forum.User)
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Record is a response stored under an idempotency key. Status stays zero
+// while the first request holding the key is still being served; Claim
+// tells that request apart from one that took the key over after its lease
+// ran out.
+type Record struct {
+	Key         string
+	Scope       string
+	Fingerprint string
+	Claim       string
+	Status      int
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+	Created     time.Time
+}
+
+func (record Record) IsComplete() bool {
+	return record.Status != 0
+}
@@ -0,0 +1,11 @@
+package domain
+
+// Response is a response stored under an idempotency key. Claim identifies
+// the request holding the key while Status is still zero.
+type Response struct {
+	Claim       string
+	Status      int
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+}
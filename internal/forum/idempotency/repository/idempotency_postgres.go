@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/rflban/parkmail-dbms/internal/forum/idempotency/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	queryClaim = `INSERT INTO idempotency_keys (key, scope, fingerprint, claim) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (key, scope) DO UPDATE
+						SET fingerprint = EXCLUDED.fingerprint, claim = EXCLUDED.claim, status = 0,
+							content_type = '', headers = '{}', body = NULL, created = now()
+						WHERE idempotency_keys.created <= $5
+							OR idempotency_keys.status = 0 AND idempotency_keys.created <= $6
+								AND idempotency_keys.fingerprint = EXCLUDED.fingerprint
+					RETURNING key;`
+	queryGet      = `SELECT fingerprint, claim, status, content_type, headers, body, created FROM idempotency_keys WHERE key = $1 AND scope = $2;`
+	queryComplete = `UPDATE idempotency_keys SET status = $4, content_type = $5, headers = $6, body = $7
+					WHERE key = $1 AND scope = $2 AND claim = $3 AND status = 0;`
+	queryRelease = `DELETE FROM idempotency_keys WHERE key = $1 AND scope = $2 AND claim = $3 AND status = 0;`
+	queryPurge   = `DELETE FROM idempotency_keys WHERE created <= $1;`
+)
+
+type IdempotencyRepositoryPostgres struct {
+	db *pgxhook.Pool
+}
+
+func New(db *pgxhook.Pool) *IdempotencyRepositoryPostgres {
+	return &IdempotencyRepositoryPostgres{
+		db: db,
+	}
+}
+
+// Claim takes the key for record unless it is held by a response stored
+// after expiredBefore or by a request in progress since after leasedBefore.
+func (r *IdempotencyRepositoryPostgres) Claim(ctx context.Context, record domain.Record, expiredBefore, leasedBefore time.Time) (bool, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Idempotency",
+		"method": "Claim",
+	})
+
+	var key string
+	err := r.db.QueryRow(ctx, queryClaim, record.Key, record.Scope, record.Fingerprint, record.Claim, expiredBefore, leasedBefore).Scan(&key)
+	if err != nil {
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return false, nil
+		}
+		log.Error(err.Error())
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *IdempotencyRepositoryPostgres) Get(ctx context.Context, key string, scope string) (domain.Record, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Idempotency",
+		"method": "Get",
+	})
+
+	record := domain.Record{
+		Key:   key,
+		Scope: scope,
+	}
+
+	err := r.db.QueryRow(ctx, queryGet, key, scope).Scan(
+		&record.Fingerprint,
+		&record.Claim,
+		&record.Status,
+		&record.ContentType,
+		&record.Headers,
+		&record.Body,
+		&record.Created,
+	)
+
+	if err != nil {
+		log.Error(err.Error())
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return record, forumErrors.NewEntityNotExistsError("idempotency_keys")
+		}
+	}
+
+	return record, err
+}
+
+func (r *IdempotencyRepositoryPostgres) Complete(ctx context.Context, record domain.Record) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Idempotency",
+		"method": "Complete",
+	})
+
+	_, err := r.db.Exec(ctx, queryComplete, record.Key, record.Scope, record.Claim,
+		record.Status, record.ContentType, record.Headers, record.Body)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *IdempotencyRepositoryPostgres) Release(ctx context.Context, key, scope, claim string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Idempotency",
+		"method": "Release",
+	})
+
+	_, err := r.db.Exec(ctx, queryRelease, key, scope, claim)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return err
+}
+
+// Purge deletes the records stored no later than expiredBefore and returns
+// how many there were.
+func (r *IdempotencyRepositoryPostgres) Purge(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Idempotency",
+		"method": "Purge",
+	})
+
+	tag, err := r.db.Exec(ctx, queryPurge, expiredBefore)
+	if err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rflban/parkmail-dbms/internal/forum/idempotency/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record domain.Record, expiredBefore, leasedBefore time.Time) (bool, error)
+	Get(ctx context.Context, key string, scope string) (domain.Record, error)
+	Complete(ctx context.Context, record domain.Record) error
+	Release(ctx context.Context, key, scope, claim string) error
+	Purge(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+type IdempotencyUseCaseImpl struct {
+	idempotencyRepo IdempotencyRepository
+	ttl             time.Duration
+	lease           time.Duration
+}
+
+// New makes a use case that keeps responses for ttl and lets a request
+// hold a key for lease before a retry may take the key over.
+func New(idempotencyRepo IdempotencyRepository, ttl, lease time.Duration) *IdempotencyUseCaseImpl {
+	return &IdempotencyUseCaseImpl{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		lease:           lease,
+	}
+}
+
+// Begin claims key within scope for a request with the given fingerprint.
+// A zero status means the caller holds the key under the returned claim and
+// must Complete or Release it; otherwise the stored response is returned
+// for replay.
+func (u *IdempotencyUseCaseImpl) Begin(ctx context.Context, key, scope, fingerprint string) (_ domain.Response, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyUseCase.Begin")
+	defer tracing.End(span, &err)
+
+	record := domain.Record{
+		Key:         key,
+		Scope:       scope,
+		Fingerprint: fingerprint,
+		Claim:       uuid.New().String(),
+	}
+
+	// A key released between the claim and the lookup is claimed again once.
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		claimed, err := u.idempotencyRepo.Claim(ctx, record, now.Add(-u.ttl), now.Add(-u.lease))
+		if err != nil {
+			return domain.Response{}, err
+		}
+		if claimed {
+			return domain.Response{Claim: record.Claim}, nil
+		}
+
+		stored, err := u.idempotencyRepo.Get(ctx, key, scope)
+		if _, notExists := err.(forumErrors.EntityNotExistsError); notExists {
+			continue
+		}
+		if err != nil {
+			return domain.Response{}, err
+		}
+
+		if stored.Fingerprint != fingerprint {
+			return domain.Response{}, forumErrors.NewInvalidArgumentError("Idempotency-Key", key)
+		}
+		if !stored.IsComplete() {
+			return domain.Response{}, forumErrors.NewConflictError("request with this idempotency key is in progress")
+		}
+
+		return domain.Response{
+			Status:      stored.Status,
+			ContentType: stored.ContentType,
+			Headers:     stored.Headers,
+			Body:        stored.Body,
+		}, nil
+	}
+
+	return domain.Response{}, forumErrors.NewConflictError("request with this idempotency key is in progress")
+}
+
+func (u *IdempotencyUseCaseImpl) Complete(ctx context.Context, key, scope string, response domain.Response) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyUseCase.Complete")
+	defer tracing.End(span, &err)
+
+	return u.idempotencyRepo.Complete(ctx, domain.Record{
+		Key:         key,
+		Scope:       scope,
+		Claim:       response.Claim,
+		Status:      response.Status,
+		ContentType: response.ContentType,
+		Headers:     response.Headers,
+		Body:        response.Body,
+	})
+}
+
+func (u *IdempotencyUseCaseImpl) Release(ctx context.Context, key, scope, claim string) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyUseCase.Release")
+	defer tracing.End(span, &err)
+
+	return u.idempotencyRepo.Release(ctx, key, scope, claim)
+}
+
+// PurgeExpired deletes the expired records every interval until ctx is
+// done.
+func (u *IdempotencyUseCaseImpl) PurgeExpired(ctx context.Context, interval time.Duration) {
+	log := ctx.Value(constants.UseCaseLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"usecase": "Idempotency",
+		"method":  "PurgeExpired",
+	})
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		purged, err := u.idempotencyRepo.Purge(ctx, time.Now().Add(-u.ttl))
+		if err != nil {
+			continue
+		}
+		if purged > 0 {
+			log.Debugf("purged %d expired idempotency keys", purged)
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -13,8 +16,14 @@ import (
 )
 
 type PostUseCase interface {
-	Patch(ctx context.Context, id int64, message *string) (models.Post, error)
-	GetDetails(ctx context.Context, id int64, related []string) (models.PostFull, error)
+	Patch(ctx context.Context, id int64, message *string, ifMatch []byte) (models.Post, string, error)
+	GetDetails(ctx context.Context, id int64, related []string) (models.PostFull, string, error)
+	GetRevisions(ctx context.Context, id int64) (models.PostRevisions, error)
+	GetAncestors(ctx context.Context, id int64) (models.Posts, error)
+	GetReplies(ctx context.Context, id int64, depth int32, limit uint64, sort string, after string) (models.PostReplies, *string, error)
+	GetRevisionDiff(ctx context.Context, id int64, number int32, to int32, mode string) (models.PostDiff, error)
+	Moderate(ctx context.Context, id int64, state string) (models.Post, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type PostHandler struct {
@@ -49,7 +58,8 @@ func (h *PostHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid id",
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -65,11 +75,12 @@ func (h *PostHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		related = append(related, strings.Split(entity, ",")...)
 	}
 
-	obtained, err := h.postUseCase.GetDetails(ctx, id, related)
+	obtained, tag, err := h.postUseCase.GetDetails(ctx, id, related)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "post not found",
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -78,7 +89,8 @@ func (h *PostHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -91,7 +103,8 @@ func (h *PostHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -99,6 +112,13 @@ func (h *PostHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
+
+	if etag.Match(rctx.Request.Header.Peek(fasthttp.HeaderIfNoneMatch), tag) {
+		rctx.SetStatusCode(fasthttp.StatusNotModified)
+		return
+	}
+
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
@@ -113,7 +133,242 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	var (
+		id  int64
+		err error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, tag, err := h.postUseCase.Patch(ctx, id, fromBody.Message, rctx.Request.Header.Peek(fasthttp.HeaderIfMatch))
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.PreconditionFailedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post was modified since it was fetched",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusPreconditionFailed)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the author or forum moderators can edit the post",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *PostHandler) GetReplies(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var (
+		id  int64
+		err error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	depthRaw := rctx.QueryArgs().Peek("depth")
+	limitRaw := rctx.QueryArgs().Peek("limit")
+	sortRaw := rctx.QueryArgs().Peek("sort")
+
+	depth, err := strconv.ParseInt(string(depthRaw), 10, 32)
+	if err != nil {
+		depth = 0
+	}
+	limit, err := strconv.ParseUint(string(limitRaw), 10, 64)
+	if err != nil {
+		limit = 0
+	}
+	sort := string(sortRaw)
+	after := string(rctx.QueryArgs().Peek("cursor"))
+
+	obtained, next, err := h.postUseCase.GetReplies(ctx, id, int32(depth), limit, sort, after)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid depth, sort or cursor",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	if next != nil {
+		rctx.Response.Header.Set(cursor.NextHeader, *next)
+	}
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *PostHandler) GetAncestors(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var (
+		id  int64
+		err error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -121,6 +376,52 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	obtained, err := h.postUseCase.GetAncestors(ctx, id)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *PostHandler) GetRevisions(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
 	var (
 		id  int64
 		err error
@@ -138,7 +439,8 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid id",
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -146,11 +448,12 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	obtained, err := h.postUseCase.Patch(ctx, id, fromBody.Message)
+	obtained, err := h.postUseCase.GetRevisions(ctx, id)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "post not found",
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -158,8 +461,20 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post is hidden by moderation",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -172,7 +487,8 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -183,3 +499,317 @@ func (h *PostHandler) Edit(rctx *fasthttp.RequestCtx) {
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
+
+func (h *PostHandler) GetRevisionDiff(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var (
+		id     int64
+		number int64
+		err    error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	numberRaw, ok := rctx.UserValue("n").(string)
+	if ok {
+		number, err = strconv.ParseInt(numberRaw, 10, 32)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse revision number: %v", rctx.UserValue("n"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid revision number",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	toRaw := rctx.QueryArgs().Peek("to")
+	modeRaw := rctx.QueryArgs().Peek("mode")
+
+	mode := string(modeRaw)
+	to, err := strconv.ParseInt(string(toRaw), 10, 32)
+	if err != nil {
+		to = 0
+	}
+
+	obtained, err := h.postUseCase.GetRevisionDiff(ctx, id, int32(number), int32(to), mode)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid diff mode",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post or revision not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post is hidden by moderation",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *PostHandler) Moderate(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var fromBody models.PostModeration
+	if err := json.Unmarshal(rctx.PostBody(), &fromBody); err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	var (
+		id  int64
+		err error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, err := h.postUseCase.Moderate(ctx, id, fromBody.State)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid state",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "moderator privileges required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *PostHandler) Delete(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	var (
+		id  int64
+		err error
+	)
+
+	idRaw, ok := rctx.UserValue("id").(string)
+	if ok {
+		id, err = strconv.ParseInt(idRaw, 10, 64)
+	}
+
+	if !ok || err != nil {
+		log.Errorf("Can't parse id: %v", rctx.UserValue("id"))
+		if err != nil {
+			log.Error(err.Error())
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	err = h.postUseCase.Delete(ctx, id)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "post not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the author or forum moderators can delete the post",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Del(fasthttp.HeaderContentType)
+	rctx.SetStatusCode(fasthttp.StatusNoContent)
+}
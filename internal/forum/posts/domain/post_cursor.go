@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+const (
+	SortFlat       = "flat"
+	SortTree       = "tree"
+	SortParentTree = "parent_tree"
+)
+
+// Cursor points at the last post of a page. Flat pages are keyed by
+// (created, id), tree pages by the post path and parent tree pages by the
+// root of the last returned tree, which Id holds in that case.
+type Cursor struct {
+	Sort    string    `json:"s"`
+	Desc    bool      `json:"d,omitempty"`
+	Created time.Time `json:"c"`
+	Id      int64     `json:"i"`
+	Path    []int64   `json:"p,omitempty"`
+}
+
+func (post Post) Cursor(sort string, desc bool) Cursor {
+	return Cursor{
+		Sort:    sort,
+		Desc:    desc,
+		Created: post.Created,
+		Id:      post.Id,
+		Path:    post.Path,
+	}
+}
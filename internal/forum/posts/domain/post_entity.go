@@ -1,10 +1,28 @@
 package domain
 
 import (
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"strconv"
 	"time"
 )
 
+const (
+	StateVisible            = "visible"
+	StateHidden             = "hidden"
+	StateDeletedByAuthor    = "deleted_by_author"
+	StateDeletedByModerator = "deleted_by_moderator"
+)
+
+func IsValidState(state string) bool {
+	switch state {
+	case StateVisible, StateHidden, StateDeletedByAuthor, StateDeletedByModerator:
+		return true
+	default:
+		return false
+	}
+}
+
 type Post struct {
 	Id       int64
 	Parent   int64
@@ -14,6 +32,42 @@ type Post struct {
 	Forum    string
 	Thread   int64
 	Created  time.Time
+	State    string
+	// Depth is the level of the post in the thread tree, roots being 0. Only
+	// tree orderings fill it in.
+	Depth int32
+	// Path lists the ids from the root of the thread tree down to the post.
+	// Only tree orderings and replies fill it in.
+	Path []int64
+}
+
+func (post Post) IsVisible() bool {
+	return post.State == "" || post.State == StateVisible
+}
+
+// ETag covers what an edit or moderation can change. It tags the post as
+// everyone but moderators see it, tombstone included.
+func (post Post) ETag() string {
+	return etag.OfFields("post", strconv.FormatInt(post.Id, 10), post.Message, post.state())
+}
+
+// ModeratedETag tags the post as moderators see it, with its state and
+// without a tombstone. Edits take it in If-Match as well as ETag.
+func (post Post) ModeratedETag() string {
+	return etag.OfFields("moderated post", strconv.FormatInt(post.Id, 10), post.Message, post.state())
+}
+
+func (post Post) state() string {
+	if post.State == "" {
+		return StateVisible
+	}
+	return post.State
+}
+
+func (post Post) Tombstone() Post {
+	post.Author = ""
+	post.Message = ""
+	return post
 }
 
 func (post Post) ToModel() models.Post {
@@ -31,6 +85,12 @@ func (post Post) ToModel() models.Post {
 	}
 }
 
+func (post Post) ToModelWithState() models.Post {
+	model := post.ToModel()
+	model.State = &post.State
+	return model
+}
+
 func FromModel(post models.Post) Post {
 	var (
 		idVal       int64
@@ -39,6 +99,7 @@ func FromModel(post models.Post) Post {
 		forumVal    string
 		threadVal   int64
 		createdVal  time.Time
+		stateVal    = StateVisible
 	)
 
 	if post.Id != nil {
@@ -59,6 +120,9 @@ func FromModel(post models.Post) Post {
 	if post.Created != nil {
 		createdVal = *post.Created
 	}
+	if post.State != nil {
+		stateVal = *post.State
+	}
 
 	return Post{
 		Id:       idVal,
@@ -69,5 +133,6 @@ func FromModel(post models.Post) Post {
 		Forum:    forumVal,
 		Thread:   threadVal,
 		Created:  createdVal,
+		State:    stateVal,
 	}
 }
@@ -0,0 +1,8 @@
+package domain
+
+// Reply is a post of a reply subtree along with the number of all of its
+// own descendants, whether or not they made it into the page.
+type Reply struct {
+	Post
+	Descendants int64
+}
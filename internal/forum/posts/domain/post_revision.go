@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"time"
+)
+
+type PostRevision struct {
+	Post    int64
+	Number  int32
+	Author  string
+	Message string
+	Created time.Time
+}
+
+func (revision PostRevision) ToModel() models.PostRevision {
+	return models.PostRevision{
+		Number:  revision.Number,
+		Author:  revision.Author,
+		Message: revision.Message,
+		Created: &revision.Created,
+	}
+}
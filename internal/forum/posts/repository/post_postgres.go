@@ -8,52 +8,134 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/posts/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	queryGetAfterBatch = `SELECT id, created, batch_idx FROM posts WHERE batch_id = $1 ORDER BY id;`
 	queryLastId        = `SELECT MAX(id) FROM posts;`
-	queryGetById       = `SELECT parent, author, message, is_edited, forum, thread, created FROM posts WHERE id = $1;`
+	queryLockAuthors   = `SELECT nickname FROM users WHERE nickname = ANY($1::CITEXT[]) FOR SHARE;`
+	queryLockParents   = `SELECT id, thread FROM posts WHERE id = ANY($1) FOR SHARE;`
+	queryGetById       = `SELECT parent, author, message, is_edited, forum, thread, created, state FROM posts WHERE id = $1;`
 	queryUpdate        = `UPDATE posts
 					SET message = COALESCE(NULLIF(TRIM($2), ''), message), is_edited = ($3 AND message != $2)
 					WHERE id = $1
-					RETURNING parent, author, message, is_edited, forum, thread, created;`
+					RETURNING parent, author, message, is_edited, forum, thread, created, state;`
+	querySetState = `UPDATE posts SET state = $2 WHERE id = $1
+					RETURNING parent, author, message, is_edited, forum, thread, created, state;`
+	queryDelete         = `DELETE FROM posts WHERE id = $1;`
+	queryGetForUpdate   = `SELECT message, state FROM posts WHERE id = $1 FOR UPDATE;`
+	queryCreateRevision = `
+					INSERT INTO post_revisions (post, number, author, message)
+						SELECT $1, COALESCE(MAX(number), 0) + 1, COALESCE(NULLIF($2, ''), $3), $4
+						FROM post_revisions
+						WHERE post = $1;`
+	queryGetRevisions   = `SELECT number, author, message, created FROM post_revisions WHERE post = $1 ORDER BY number;`
+	queryGetRevision    = `SELECT number, author, message, created FROM post_revisions WHERE post = $1 AND number = $2;`
+	queryCountRevisions = `SELECT COUNT(*) FROM post_revisions WHERE post = $1;`
+	queryCountInThread  = `SELECT COUNT(*) FROM posts WHERE thread = $1;`
+	queryCountRoots     = `SELECT COUNT(*) FROM posts WHERE thread = $1 AND parent = 0;`
+	queryGetAncestors   = `SELECT id, parent, author, message, is_edited, forum, thread, created, state, array_length(path, 1) - 1
+					FROM posts
+					WHERE id = ANY((SELECT path FROM posts WHERE id = $1))
+					ORDER BY array_length(path, 1);`
 )
 
 type PostRepositoryPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *PostRepositoryPostgres {
+func New(db *pgxhook.Pool) *PostRepositoryPostgres {
 	return &PostRepositoryPostgres{
 		db: db,
 	}
 }
 
-func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post) ([]domain.Post, error) {
+func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post, partial bool) ([]domain.Post, []forumErrors.ItemError, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
 		"method": "Create",
 	})
 
+	obtained, itemErrors, err := r.insertBatch(ctx, log, posts, partial, false)
+
+	// Posts are checked one by one only once the batch failed on a missing
+	// author or parent, so a valid batch costs no more than its insert.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.SQLState() == "23514" || pgErr.SQLState() == "23503") {
+		obtained, itemErrors, err = r.insertBatch(ctx, log, posts, partial, true)
+	}
+
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case "23514":
+			return nil, itemErrors, forumErrors.NewConflictError(
+				pgErr.Message,
+			)
+		case "23503":
+			return nil, itemErrors, forumErrors.NewEntityNotExistsError("users or forum")
+		}
+	}
+
+	return obtained, itemErrors, err
+}
+
+// insertBatch copies the posts in one transaction. A checked batch is
+// validated first: its invalid posts are reported and, for a partial
+// batch, left out of the copy.
+func (r *PostRepositoryPostgres) insertBatch(ctx context.Context, log *logrus.Entry, posts []domain.Post, partial bool, checked bool) ([]domain.Post, []forumErrors.ItemError, error) {
 	batchID := uuid.New()
 
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, nil, err
+	}
+
+	rollback := func() {
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+	}
+
+	var itemErrors []forumErrors.ItemError
+	if checked {
+		itemErrors, err = r.validateBatch(ctx, tx, posts)
+		if err != nil {
+			log.Error(err.Error())
+			rollback()
+			return nil, nil, err
+		}
+	}
+
+	if len(itemErrors) > 0 && !partial {
+		rollback()
+		return nil, itemErrors, forumErrors.NewBatchError(itemErrors)
+	}
+
+	invalid := make(map[int]bool, len(itemErrors))
+	for _, itemError := range itemErrors {
+		invalid[itemError.Index] = true
+	}
+
+	valid := make([]int, 0, len(posts))
+	for idx := range posts {
+		if !invalid[idx] {
+			valid = append(valid, idx)
+		}
 	}
 
 	now := time.Now()
 
-	copied, err := r.db.CopyFrom(ctx, pgx.Identifier{"posts"}, []string{
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"posts"}, []string{
 		"parent",
 		"author",
 		"message",
@@ -62,20 +144,22 @@ func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post
 		"created",
 		"batch_id",
 		"batch_idx",
-	}, pgx.CopyFromSlice(len(posts), func(i int) ([]interface{}, error) {
-		if posts[i].Created.Equal(time.Time{}) {
-			posts[i].Created = now
+	}, pgx.CopyFromSlice(len(valid), func(i int) ([]interface{}, error) {
+		idx := valid[i]
+
+		if posts[idx].Created.Equal(time.Time{}) {
+			posts[idx].Created = now
 		}
 
 		post := []interface{}{
-			posts[i].Parent,
-			posts[i].Author,
-			posts[i].Message,
-			posts[i].Forum,
-			posts[i].Thread,
-			posts[i].Created,
+			posts[idx].Parent,
+			posts[idx].Author,
+			posts[idx].Message,
+			posts[idx].Forum,
+			posts[idx].Thread,
+			posts[idx].Created,
 			batchID.String(),
-			i,
+			idx,
 		}
 
 		return post, nil
@@ -83,49 +167,28 @@ func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post
 
 	if err != nil {
 		log.Error(err.Error())
-
-		if err := tx.Rollback(ctx); err != nil {
-			log.Error(err.Error())
-		}
-
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			switch pgErr.SQLState() {
-			case "23514":
-				return nil, forumErrors.NewConflictError(
-					pgErr.Message,
-				)
-			case "23503":
-				return nil, forumErrors.NewEntityNotExistsError("users or forum")
-			}
-		}
-
-		return nil, err
+		rollback()
+		return nil, itemErrors, err
 	}
 
-	if int(copied) != len(posts) {
+	if int(copied) != len(valid) {
 		log.
-			WithField("copied", fmt.Sprintf("%d/%d", copied, len(posts))).
+			WithField("copied", fmt.Sprintf("%d/%d", copied, len(valid))).
 			Errorf("Failed bulk insert")
+		rollback()
 
-		if err := tx.Rollback(ctx); err != nil {
-			log.Error(err.Error())
-		}
-
-		return nil, err
+		return nil, itemErrors, fmt.Errorf("copied %d of %d posts", copied, len(valid))
 	}
 
-	rows, err := r.db.Query(ctx, queryGetAfterBatch, batchID.String())
+	rows, err := tx.Query(ctx, queryGetAfterBatch, batchID.String())
 	if err != nil {
 		log.Error(err.Error())
-		if err := tx.Rollback(ctx); err != nil {
-			return nil, err
-		}
-		return nil, err
+		rollback()
+		return nil, itemErrors, err
 	}
 
 	var batch_idx int
-	obtained := make([]domain.Post, 0, rows.CommandTag().RowsAffected())
+	obtained := make([]domain.Post, 0, len(valid))
 	post := domain.Post{}
 
 	for rows.Next() {
@@ -136,10 +199,9 @@ func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post
 		)
 		if err != nil {
 			log.Error(err.Error())
-			if err := tx.Rollback(ctx); err != nil {
-				return nil, err
-			}
-			return nil, err
+			rows.Close()
+			rollback()
+			return nil, itemErrors, err
 		}
 		post.Parent = posts[batch_idx].Parent
 		post.Author = posts[batch_idx].Author
@@ -147,16 +209,89 @@ func (r *PostRepositoryPostgres) Create(ctx context.Context, posts []domain.Post
 		post.IsEdited = posts[batch_idx].IsEdited
 		post.Forum = posts[batch_idx].Forum
 		post.Thread = posts[batch_idx].Thread
+		post.State = domain.StateVisible
 
 		obtained = append(obtained, post)
 	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		log.Error(err.Error())
+		rollback()
+		return nil, itemErrors, err
+	}
 
 	err = tx.Commit(ctx)
 
-	return obtained, err
+	return obtained, itemErrors, err
 }
 
-func (r *PostRepositoryPostgres) Patch(ctx context.Context, id int64, message *string) (domain.Post, error) {
+// validateBatch checks authors and parents of a batch, locking them until
+// the batch is committed, so that every invalid post is reported by its
+// index instead of the first failing row aborting the copy.
+func (r *PostRepositoryPostgres) validateBatch(ctx context.Context, tx pgx.Tx, posts []domain.Post) ([]forumErrors.ItemError, error) {
+	authors := make([]string, 0, len(posts))
+	parents := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		authors = append(authors, post.Author)
+		if post.Parent != 0 {
+			parents = append(parents, post.Parent)
+		}
+	}
+
+	known := make(map[string]bool, len(authors))
+	rows, err := tx.Query(ctx, queryLockAuthors, authors)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var nickname string
+		if err := rows.Scan(&nickname); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		known[strings.ToLower(nickname)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	parentThreads := make(map[int64]int64, len(parents))
+	rows, err = tx.Query(ctx, queryLockParents, parents)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id, thread int64
+		if err := rows.Scan(&id, &thread); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		parentThreads[id] = thread
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	itemErrors := make([]forumErrors.ItemError, 0)
+	for idx, post := range posts {
+		if !known[strings.ToLower(post.Author)] {
+			itemErrors = append(itemErrors, forumErrors.NewAuthorUnknownError(idx, post.Author))
+		}
+		if thread, ok := parentThreads[post.Parent]; post.Parent != 0 && (!ok || thread != post.Thread) {
+			itemErrors = append(itemErrors, forumErrors.NewParentNotInThreadError(idx, post.Parent))
+		}
+	}
+
+	return itemErrors, nil
+}
+
+// Patch edits the message of the post. A non-empty ifMatch has to match the
+// ETag of the post as it is once locked, so concurrent edits can not slip
+// in between the check and the update.
+func (r *PostRepositoryPostgres) Patch(ctx context.Context, id int64, message *string, editor string, ifMatch []byte) (domain.Post, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
 		"method": "Patch",
@@ -165,7 +300,163 @@ func (r *PostRepositoryPostgres) Patch(ctx context.Context, id int64, message *s
 	post := domain.Post{
 		Id: id,
 	}
-	err := r.db.QueryRow(ctx, queryUpdate, id, message, message != nil).Scan(
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return post, err
+	}
+
+	previous := domain.Post{
+		Id: id,
+	}
+	err = tx.QueryRow(ctx, queryGetForUpdate, id).Scan(&previous.Message, &previous.State)
+	if err == nil && len(ifMatch) != 0 &&
+		!etag.MatchStrong(ifMatch, previous.ETag()) && !etag.MatchStrong(ifMatch, previous.ModeratedETag()) {
+		err = forumErrors.NewPreconditionFailedError("post")
+	}
+	if err == nil {
+		err = tx.QueryRow(ctx, queryUpdate, id, message, message != nil).Scan(
+			&post.Parent,
+			&post.Author,
+			&post.Message,
+			&post.IsEdited,
+			&post.Forum,
+			&post.Thread,
+			&post.Created,
+			&post.State,
+		)
+	}
+	if err == nil && post.Message != previous.Message {
+		_, err = tx.Exec(ctx, queryCreateRevision, id, editor, post.Author, previous.Message)
+	}
+
+	if err != nil {
+		log.Error(err.Error())
+
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return post, forumErrors.NewEntityNotExistsError("posts")
+		}
+		return post, err
+	}
+
+	err = tx.Commit(ctx)
+
+	return post, err
+}
+
+func (r *PostRepositoryPostgres) GetRevisions(ctx context.Context, id int64) ([]domain.PostRevision, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "GetRevisions",
+	})
+
+	rows, err := r.db.Query(ctx, queryGetRevisions, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]domain.PostRevision, 0)
+	revision := domain.PostRevision{
+		Post: id,
+	}
+
+	for rows.Next() {
+		err := rows.Scan(
+			&revision.Number,
+			&revision.Author,
+			&revision.Message,
+			&revision.Created,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (r *PostRepositoryPostgres) GetRevision(ctx context.Context, id int64, number int32) (domain.PostRevision, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "GetRevision",
+	})
+
+	revision := domain.PostRevision{
+		Post: id,
+	}
+	err := r.db.QueryRow(ctx, queryGetRevision, id, number).Scan(
+		&revision.Number,
+		&revision.Author,
+		&revision.Message,
+		&revision.Created,
+	)
+
+	if err != nil {
+		log.Error(err.Error())
+		if err.Error() == pgx.ErrNoRows.Error() {
+			return revision, forumErrors.NewEntityNotExistsError("post_revisions")
+		}
+	}
+
+	return revision, err
+}
+
+func (r *PostRepositoryPostgres) CountRevisions(ctx context.Context, id int64) (int32, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "CountRevisions",
+	})
+
+	var count int32
+	err := r.db.QueryRow(ctx, queryCountRevisions, id).Scan(&count)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return count, err
+}
+
+// CountInThread counts the posts of the thread, or only its root posts,
+// the units a parent_tree page is limited in.
+func (r *PostRepositoryPostgres) CountInThread(ctx context.Context, thread int64, rootsOnly bool) (int64, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "CountInThread",
+	})
+
+	query := queryCountInThread
+	if rootsOnly {
+		query = queryCountRoots
+	}
+
+	var count int64
+	err := r.db.QueryRow(ctx, query, thread).Scan(&count)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return count, err
+}
+
+func (r *PostRepositoryPostgres) SetState(ctx context.Context, id int64, state string) (domain.Post, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "SetState",
+	})
+
+	post := domain.Post{
+		Id: id,
+	}
+	err := r.db.QueryRow(ctx, querySetState, id, state).Scan(
 		&post.Parent,
 		&post.Author,
 		&post.Message,
@@ -173,6 +464,7 @@ func (r *PostRepositoryPostgres) Patch(ctx context.Context, id int64, message *s
 		&post.Forum,
 		&post.Thread,
 		&post.Created,
+		&post.State,
 	)
 
 	if err != nil {
@@ -185,6 +477,25 @@ func (r *PostRepositoryPostgres) Patch(ctx context.Context, id int64, message *s
 	return post, err
 }
 
+func (r *PostRepositoryPostgres) Delete(ctx context.Context, id int64) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "Delete",
+	})
+
+	tag, err := r.db.Exec(ctx, queryDelete, id)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("posts")
+	}
+
+	return nil
+}
+
 func (r *PostRepositoryPostgres) GetById(ctx context.Context, id int64) (domain.Post, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
@@ -202,6 +513,7 @@ func (r *PostRepositoryPostgres) GetById(ctx context.Context, id int64) (domain.
 		&post.Forum,
 		&post.Thread,
 		&post.Created,
+		&post.State,
 	)
 
 	if err != nil {
@@ -214,7 +526,142 @@ func (r *PostRepositoryPostgres) GetById(ctx context.Context, id int64) (domain.
 	return post, err
 }
 
-func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error) {
+func (r *PostRepositoryPostgres) GetAncestors(ctx context.Context, id int64) ([]domain.Post, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "GetAncestors",
+	})
+
+	rows, err := r.db.Query(ctx, queryGetAncestors, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]domain.Post, 0)
+	post := domain.Post{}
+
+	for rows.Next() {
+		err := rows.Scan(
+			&post.Id,
+			&post.Parent,
+			&post.Author,
+			&post.Message,
+			&post.IsEdited,
+			&post.Forum,
+			&post.Thread,
+			&post.Created,
+			&post.State,
+			&post.Depth,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	if len(posts) == 0 {
+		return nil, forumErrors.NewEntityNotExistsError("posts")
+	}
+
+	return posts, nil
+}
+
+// GetReplies returns the descendants of a post following after, if it is
+// given. Descendants are counted in one pass over the subtree: every post
+// counts once for each post on its path.
+func (r *PostRepositoryPostgres) GetReplies(ctx context.Context, id int64, depth int32, after *domain.Cursor, limit uint64, sort string) ([]domain.Reply, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Post",
+		"method": "GetReplies",
+	})
+
+	// Descendants of a post share its path as a prefix, so they all sort
+	// between that path and the path extended by the largest possible id,
+	// which keeps both ranges on the (thread, path) index.
+	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(
+			"p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created, p.state, p.path",
+			"COALESCE(c.descendants, 0)",
+		).
+		Prefix(`WITH root AS (SELECT thread, path FROM posts WHERE id = ?),
+			counts AS (
+				SELECT ancestor, COUNT(*) - 1 AS descendants
+				  FROM posts d, root, unnest(d.path) AS ancestor
+				 WHERE d.thread = root.thread
+				   AND d.path > root.path
+				   AND d.path < root.path || 9223372036854775807::BIGINT
+				 GROUP BY ancestor
+			)`, id).
+		From("posts p").
+		Join("root ON p.thread = root.thread").
+		LeftJoin("counts c ON c.ancestor = p.id").
+		Where("p.path > root.path").
+		Where("p.path < root.path || 9223372036854775807::BIGINT")
+
+	if depth > 0 {
+		queryBuilder = queryBuilder.Where("array_length(p.path, 1) <= array_length(root.path, 1) + ?", depth)
+	}
+
+	if sort == domain.SortFlat {
+		if after != nil {
+			queryBuilder = queryBuilder.Where("(p.created, p.id) > (?, ?)", after.Created, after.Id)
+		}
+		queryBuilder = queryBuilder.OrderBy("p.created ASC, p.id ASC")
+	} else {
+		if after != nil {
+			queryBuilder = queryBuilder.Where("p.path > ?", after.Path)
+		}
+		queryBuilder = queryBuilder.OrderBy("p.path ASC")
+	}
+
+	if limit > 0 {
+		queryBuilder = queryBuilder.Limit(limit)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, query+";", args...)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	replies := make([]domain.Reply, 0, rows.CommandTag().RowsAffected())
+
+	for rows.Next() {
+		reply := domain.Reply{}
+		err := rows.Scan(
+			&reply.Id,
+			&reply.Parent,
+			&reply.Author,
+			&reply.Message,
+			&reply.IsEdited,
+			&reply.Forum,
+			&reply.Thread,
+			&reply.Created,
+			&reply.State,
+			&reply.Path,
+			&reply.Descendants,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		replies = append(replies, reply)
+	}
+
+	return replies, nil
+}
+
+func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
 		"method": "GetFromThreadFlat",
@@ -224,7 +671,7 @@ func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread s
 	threadIsNum := err == nil
 
 	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id, parent, author, message, is_edited, forum, thread, created").
+		Select("id, parent, author, message, is_edited, forum, thread, created, state").
 		From("posts")
 
 	if threadIsNum {
@@ -233,7 +680,13 @@ func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread s
 		queryBuilder = queryBuilder.Where("thread = (SELECT id FROM threads WHERE slug = ?)", thread)
 	}
 
-	if since > 0 {
+	if after != nil {
+		if desc {
+			queryBuilder = queryBuilder.Where("(created, id) < (?, ?)", after.Created, after.Id)
+		} else {
+			queryBuilder = queryBuilder.Where("(created, id) > (?, ?)", after.Created, after.Id)
+		}
+	} else if since > 0 {
 		if desc {
 			queryBuilder = queryBuilder.Where("id < ?", since)
 		} else {
@@ -279,6 +732,7 @@ func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread s
 			&post.Forum,
 			&post.Thread,
 			&post.Created,
+			&post.State,
 		)
 		if err != nil {
 			log.Error(err.Error())
@@ -290,7 +744,9 @@ func (r *PostRepositoryPostgres) GetFromThreadFlat(ctx context.Context, thread s
 	return posts, nil
 }
 
-func (r *PostRepositoryPostgres) GetFromThreadTree(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error) {
+// GetFromThreadTree returns thread posts in tree order following after, if
+// it is given, or else the post with the since id.
+func (r *PostRepositoryPostgres) GetFromThreadTree(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
 		"method": "GetFromThreadTree",
@@ -300,7 +756,7 @@ func (r *PostRepositoryPostgres) GetFromThreadTree(ctx context.Context, thread s
 	threadIsNum := err == nil
 
 	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id, parent, author, message, is_edited, forum, thread, created").
+		Select("id, parent, author, message, is_edited, forum, thread, created, state, array_length(path, 1) - 1, path").
 		From("posts")
 
 	if threadIsNum {
@@ -309,7 +765,13 @@ func (r *PostRepositoryPostgres) GetFromThreadTree(ctx context.Context, thread s
 		queryBuilder = queryBuilder.Where("thread = (SELECT id FROM threads WHERE slug = ?)", thread)
 	}
 
-	if since > 0 {
+	if after != nil {
+		if desc {
+			queryBuilder = queryBuilder.Where("path < ?", after.Path)
+		} else {
+			queryBuilder = queryBuilder.Where("path > ?", after.Path)
+		}
+	} else if since > 0 {
 		if desc {
 			queryBuilder = queryBuilder.Where("path < (SELECT path FROM posts WHERE id = ?)", since)
 		} else {
@@ -333,47 +795,20 @@ func (r *PostRepositoryPostgres) GetFromThreadTree(ctx context.Context, thread s
 		return nil, err
 	}
 
-	log.Info(query)
-	log.Info(args)
-	rows, err := r.db.Query(ctx, query+";", args...)
-	if err != nil {
-		log.Error(err.Error())
-		return nil, err
-	}
-	defer rows.Close()
-
-	posts := make([]domain.Post, 0, rows.CommandTag().RowsAffected())
-	post := domain.Post{}
-
-	for rows.Next() {
-		err := rows.Scan(
-			&post.Id,
-			&post.Parent,
-			&post.Author,
-			&post.Message,
-			&post.IsEdited,
-			&post.Forum,
-			&post.Thread,
-			&post.Created,
-		)
-		if err != nil {
-			log.Error(err.Error())
-			return nil, err
-		}
-		posts = append(posts, post)
-	}
-
-	return posts, nil
+	return r.queryTree(ctx, log, query, args)
 }
 
-func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error) {
+// GetFromThreadParentTree returns whole trees of thread posts, limit of
+// them, starting after the root of the tree after points into, if it is
+// given, or else after the root of the post with the since id.
+func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Post",
 		"method": "GetFromThreadParentTree",
 	})
 
 	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id, parent, author, message, is_edited, forum, thread, created").
+		Select("id, parent, author, message, is_edited, forum, thread, created, state, array_length(path, 1) - 1, path").
 		From("posts")
 
 	_, err := strconv.ParseInt(thread, 10, 64)
@@ -386,66 +821,40 @@ func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, th
 		threadSqlVal = `(SELECT id FROM threads WHERE slug = ?)`
 	}
 
-	if since > 0 {
-		if desc {
-			queryBuilder = queryBuilder.
-				Where(
-					fmt.Sprintf(`path[1] IN (
-							SELECT id
-							FROM posts
-							WHERE thread = %s AND parent = 0 AND path[1] < (
-								SELECT path[1]
-								FROM posts
-								WHERE id = ?)
-							ORDER BY id DESC LIMIT ?)`, threadSqlVal),
-					thread,
-					since,
-					limit,
-				).
-				OrderBy(`path[1] DESC, path ASC, id ASC`)
-		} else {
-			queryBuilder = queryBuilder.
-				Where(
-					fmt.Sprintf(`path[1] IN (
-							SELECT id
-							FROM posts
-							WHERE thread = %s AND parent = 0 AND path[1] > (
-								SELECT path[1]
-								FROM posts
-								WHERE id = ?)
-							ORDER BY id ASC LIMIT ?)`, threadSqlVal),
-					thread,
-					since,
-					limit,
-				).
-				OrderBy(`path ASC, id ASC`)
-		}
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+
+	rootArgs := []interface{}{thread}
+	rootFilter := ""
+	if after != nil {
+		rootFilter = fmt.Sprintf(" AND path[1] %s ?", op)
+		rootArgs = append(rootArgs, after.Path[0])
+	} else if since > 0 {
+		rootFilter = fmt.Sprintf(" AND path[1] %s (SELECT path[1] FROM posts WHERE id = ?)", op)
+		rootArgs = append(rootArgs, since)
+	}
+
+	rootLimit := ""
+	if limit > 0 {
+		rootLimit = " LIMIT ?"
+		rootArgs = append(rootArgs, limit)
+	}
+
+	queryBuilder = queryBuilder.Where(
+		fmt.Sprintf(`path[1] IN (
+				SELECT id
+				FROM posts
+				WHERE thread = %s AND parent = 0%s
+				ORDER BY id %s%s)`, threadSqlVal, rootFilter, order, rootLimit),
+		rootArgs...,
+	)
+
+	if desc {
+		queryBuilder = queryBuilder.OrderBy(`path[1] DESC, path ASC, id ASC`)
 	} else {
-		if desc {
-			queryBuilder = queryBuilder.
-				Where(
-					fmt.Sprintf(`path[1] IN (
-							SELECT id
-							FROM posts
-							WHERE thread = %s AND parent = 0
-							ORDER BY id DESC LIMIT ?)`, threadSqlVal),
-					thread,
-					limit,
-				).
-				OrderBy(`path[1] DESC, path ASC, id ASC`)
-		} else {
-			queryBuilder = queryBuilder.
-				Where(
-					fmt.Sprintf(`path[1] IN (
-							SELECT id
-							FROM posts
-							WHERE thread = %s AND parent = 0
-							ORDER BY id ASC LIMIT ?)`, threadSqlVal),
-					thread,
-					limit,
-				).
-				OrderBy(`path ASC, id ASC`)
-		}
+		queryBuilder = queryBuilder.OrderBy(`path ASC, id ASC`)
 	}
 
 	query, args, err := queryBuilder.ToSql()
@@ -454,6 +863,12 @@ func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, th
 		return nil, err
 	}
 
+	return r.queryTree(ctx, log, query, args)
+}
+
+// queryTree runs a tree ordered query whose rows end with the depth and
+// the path of the post.
+func (r *PostRepositoryPostgres) queryTree(ctx context.Context, log *logrus.Entry, query string, args []interface{}) ([]domain.Post, error) {
 	rows, err := r.db.Query(ctx, query+";", args...)
 	if err != nil {
 		log.Error(err.Error())
@@ -462,9 +877,9 @@ func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, th
 	defer rows.Close()
 
 	posts := make([]domain.Post, 0, rows.CommandTag().RowsAffected())
-	post := domain.Post{}
 
 	for rows.Next() {
+		post := domain.Post{}
 		err := rows.Scan(
 			&post.Id,
 			&post.Parent,
@@ -474,6 +889,9 @@ func (r *PostRepositoryPostgres) GetFromThreadParentTree(ctx context.Context, th
 			&post.Forum,
 			&post.Thread,
 			&post.Created,
+			&post.State,
+			&post.Depth,
+			&post.Path,
 		)
 		if err != nil {
 			log.Error(err.Error())
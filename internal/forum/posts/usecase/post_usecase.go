@@ -8,18 +8,38 @@ import (
 	threadsDomain "github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	usersDomain "github.com/rflban/parkmail-dbms/internal/forum/users/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/diff"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"strconv"
 )
 
+const (
+	defaultRepliesLimit = 100
+	maxRepliesLimit     = 1000
+)
+
 type PostRepository interface {
-	Create(ctx context.Context, posts []domain.Post) ([]domain.Post, error)
-	Patch(ctx context.Context, id int64, message *string) (domain.Post, error)
+	Create(ctx context.Context, posts []domain.Post, partial bool) ([]domain.Post, []forumErrors.ItemError, error)
+	Patch(ctx context.Context, id int64, message *string, editor string, ifMatch []byte) (domain.Post, error)
+	GetRevisions(ctx context.Context, id int64) ([]domain.PostRevision, error)
+	GetRevision(ctx context.Context, id int64, number int32) (domain.PostRevision, error)
+	CountRevisions(ctx context.Context, id int64) (int32, error)
+	CountInThread(ctx context.Context, thread int64, rootsOnly bool) (int64, error)
+	SetState(ctx context.Context, id int64, state string) (domain.Post, error)
+	Delete(ctx context.Context, id int64) error
 	GetById(ctx context.Context, id int64) (domain.Post, error)
-	GetFromThreadFlat(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error)
-	GetFromThreadTree(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error)
-	GetFromThreadParentTree(ctx context.Context, thread string, since int64, limit uint64, desc bool) ([]domain.Post, error)
+	GetAncestors(ctx context.Context, id int64) ([]domain.Post, error)
+	GetReplies(ctx context.Context, id int64, depth int32, after *domain.Cursor, limit uint64, sort string) ([]domain.Reply, error)
+	GetFromThreadFlat(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error)
+	GetFromThreadTree(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error)
+	GetFromThreadParentTree(ctx context.Context, thread string, since int64, after *domain.Cursor, limit uint64, desc bool) ([]domain.Post, error)
 }
 
 type UserRepository interface {
@@ -33,6 +53,7 @@ type ThreadRepository interface {
 
 type ForumRepository interface {
 	GetBySlug(ctx context.Context, slug string) (forumsDomain.Forum, error)
+	CanModerate(ctx context.Context, slug string, nickname string) (bool, error)
 }
 
 type PostUseCaseImpl struct {
@@ -56,7 +77,18 @@ func New(
 	}
 }
 
-func (u *PostUseCaseImpl) Create(ctx context.Context, threadSlugOrId string, posts models.Posts) (models.Posts, error) {
+func (u *PostUseCaseImpl) Create(ctx context.Context, threadSlugOrId string, posts models.Posts, partial bool) (_ models.PostsBatch, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.Create")
+	defer tracing.End(span, &err)
+
+	batch := models.PostsBatch{}
+
+	for _, post := range posts {
+		if err := identity.ActAs(ctx, post.Author); err != nil {
+			return batch, err
+		}
+	}
+
 	var thread threadsDomain.Thread
 	threadId, err := strconv.ParseInt(threadSlugOrId, 10, 64)
 
@@ -67,7 +99,7 @@ func (u *PostUseCaseImpl) Create(ctx context.Context, threadSlugOrId string, pos
 	}
 
 	if err != nil {
-		return nil, err
+		return batch, err
 	}
 
 	threadId32 := int32(thread.Id)
@@ -78,31 +110,223 @@ func (u *PostUseCaseImpl) Create(ctx context.Context, threadSlugOrId string, pos
 		toCreate = append(toCreate, domain.FromModel(post))
 	}
 
-	created, err := u.postRepo.Create(ctx, toCreate)
+	created, itemErrors, err := u.postRepo.Create(ctx, toCreate, partial)
 
 	if err != nil {
-		return nil, err
+		return batch, err
 	}
+	metrics.PostsCreated(len(created))
 
-	obtained := make(models.Posts, 0, len(created))
+	batch.Created = make(models.Posts, 0, len(created))
 	for _, post := range created {
-		obtained = append(obtained, post.ToModel())
+		batch.Created = append(batch.Created, post.ToModel())
+	}
+
+	batch.Errors = make(models.PostErrors, 0, len(itemErrors))
+	for _, itemError := range itemErrors {
+		batch.Errors = append(batch.Errors, models.PostError{
+			Index:   itemError.Index,
+			Code:    itemError.Code,
+			Message: itemError.Message,
+		})
+	}
+
+	return batch, nil
+}
+
+// Patch edits the message of the post and returns it with its new ETag.
+// A non-empty ifMatch makes the edit conditional on the current ETag.
+func (u *PostUseCaseImpl) Patch(ctx context.Context, id int64, message *string, ifMatch []byte) (_ models.Post, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.Patch")
+	defer tracing.End(span, &err)
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return models.Post{}, "", err
+	}
+
+	if err := identity.Manage(ctx, u.forumRepo, post.Forum, post.Author); err != nil {
+		return models.Post{}, "", err
+	}
+
+	editor, _ := identity.Caller(ctx)
+	edited, err := u.postRepo.Patch(ctx, id, message, editor, ifMatch)
+	if err != nil {
+		return models.Post{}, "", err
+	}
+
+	return edited.ToModel(), edited.ETag(), nil
+}
+
+func (u *PostUseCaseImpl) GetRevisions(ctx context.Context, id int64) (_ models.PostRevisions, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetRevisions")
+	defer tracing.End(span, &err)
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !post.IsVisible() {
+		canModerate, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+		if err != nil {
+			return nil, err
+		}
+		if !canModerate {
+			return nil, forumErrors.NewForbiddenError("view revisions of a moderated post")
+		}
+	}
+
+	revisions, err := u.postRepo.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	obtained := make(models.PostRevisions, 0, len(revisions))
+	for _, revision := range revisions {
+		obtained = append(obtained, revision.ToModel())
 	}
 
 	return obtained, nil
 }
 
-func (u *PostUseCaseImpl) Patch(ctx context.Context, id int64, message *string) (models.Post, error) {
-	edited, err := u.postRepo.Patch(ctx, id, message)
-	return edited.ToModel(), err
+func (u *PostUseCaseImpl) GetRevisionDiff(ctx context.Context, id int64, number int32, to int32, mode string) (_ models.PostDiff, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetRevisionDiff")
+	defer tracing.End(span, &err)
+
+	postDiff := models.PostDiff{
+		Post: id,
+		From: number,
+		To:   to,
+		Mode: mode,
+	}
+
+	if postDiff.Mode == "" {
+		postDiff.Mode = "line"
+	}
+	if postDiff.Mode != "line" && postDiff.Mode != "word" {
+		return postDiff, forumErrors.NewInvalidArgumentError("mode", mode)
+	}
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return postDiff, err
+	}
+
+	if !post.IsVisible() {
+		canModerate, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+		if err != nil {
+			return postDiff, err
+		}
+		if !canModerate {
+			return postDiff, forumErrors.NewForbiddenError("view revisions of a moderated post")
+		}
+	}
+
+	count, err := u.postRepo.CountRevisions(ctx, id)
+	if err != nil {
+		return postDiff, err
+	}
+
+	if postDiff.To == 0 {
+		postDiff.To = postDiff.From + 1
+	}
+	if postDiff.From < 1 || postDiff.From > count || postDiff.To < 1 || postDiff.To > count+1 {
+		return postDiff, forumErrors.NewEntityNotExistsError("post_revisions")
+	}
+
+	message := func(number int32) (string, error) {
+		if number == count+1 {
+			return post.Message, nil
+		}
+		revision, err := u.postRepo.GetRevision(ctx, id, number)
+		return revision.Message, err
+	}
+
+	fromMessage, err := message(postDiff.From)
+	if err != nil {
+		return postDiff, err
+	}
+	toMessage, err := message(postDiff.To)
+	if err != nil {
+		return postDiff, err
+	}
+
+	var chunks []diff.Chunk
+	if postDiff.Mode == "word" {
+		chunks = diff.Words(fromMessage, toMessage)
+	} else {
+		chunks = diff.Lines(fromMessage, toMessage)
+	}
+
+	postDiff.Chunks = make([]models.DiffChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		postDiff.Chunks = append(postDiff.Chunks, models.DiffChunk{
+			Op:   chunk.Op,
+			Text: chunk.Text,
+		})
+	}
+
+	return postDiff, nil
+}
+
+func (u *PostUseCaseImpl) Moderate(ctx context.Context, id int64, state string) (_ models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.Moderate")
+	defer tracing.End(span, &err)
+
+	if !domain.IsValidState(state) {
+		return models.Post{}, forumErrors.NewInvalidArgumentError("state", state)
+	}
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	canModerate, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	if !canModerate {
+		return models.Post{}, forumErrors.NewForbiddenError("moderate posts")
+	}
+
+	moderated, err := u.postRepo.SetState(ctx, id, state)
+	return moderated.ToModelWithState(), err
+}
+
+func (u *PostUseCaseImpl) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.Delete")
+	defer tracing.End(span, &err)
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := identity.Manage(ctx, u.forumRepo, post.Forum, post.Author); err != nil {
+		return err
+	}
+
+	return u.postRepo.Delete(ctx, id)
 }
 
-func (u *PostUseCaseImpl) GetById(ctx context.Context, id int64) (models.Post, error) {
+func (u *PostUseCaseImpl) GetById(ctx context.Context, id int64) (_ models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetById")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.postRepo.GetById(ctx, id)
 	return obtained.ToModel(), err
 }
 
-func (u *PostUseCaseImpl) GetDetails(ctx context.Context, id int64, related []string) (models.PostFull, error) {
+// GetDetails returns the post with the related entities asked for, along
+// with an ETag of the whole response. Without related entities it is the
+// ETag of the post as the caller sees it, which edits take in If-Match.
+func (u *PostUseCaseImpl) GetDetails(ctx context.Context, id int64, related []string) (_ models.PostFull, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetDetails")
+	defer tracing.End(span, &err)
+
 	log := ctx.Value(constants.UseCaseLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"usecase": "Post",
 		"method":  "GetDetails",
@@ -110,33 +334,47 @@ func (u *PostUseCaseImpl) GetDetails(ctx context.Context, id int64, related []st
 
 	postFull := models.PostFull{}
 	post, err := u.postRepo.GetById(ctx, id)
-	postModel := post.ToModel()
+	if err != nil {
+		return postFull, "", err
+	}
+
+	privileged, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+	if err != nil {
+		return postFull, "", err
+	}
+
+	postModel := present(post, privileged)
 	postFull.Post = &postModel
 
+	tag := post.ETag()
+	if privileged {
+		tag = post.ModeratedETag()
+	}
+	tags := []string{tag}
+
 	var (
-		userObtained   = false
-		threadObtained = false
-		forumObtained  = false
+		userObtained      = false
+		threadObtained    = false
+		forumObtained     = false
+		revisionsObtained = false
+		ancestorsObtained = false
 	)
 
-	if err != nil {
-		return postFull, err
-	}
-
 	for _, entity := range related {
 		switch entity {
 		case "user":
-			if userObtained {
+			if userObtained || !(privileged || post.IsVisible()) {
 				break
 			}
 
 			user, err := u.userRepo.GetByNickname(ctx, post.Author)
 			if err != nil {
-				return postFull, err
+				return postFull, "", err
 			}
 
 			userModel := user.ToModel()
 			postFull.Author = &userModel
+			tags = append(tags, user.ETag())
 
 			userObtained = true
 		case "thread":
@@ -146,11 +384,12 @@ func (u *PostUseCaseImpl) GetDetails(ctx context.Context, id int64, related []st
 
 			thread, err := u.threadRepo.GetById(ctx, post.Thread)
 			if err != nil {
-				return postFull, err
+				return postFull, "", err
 			}
 
 			threadModel := thread.ToModel()
 			postFull.Thread = &threadModel
+			tags = append(tags, thread.ETag())
 
 			threadObtained = true
 		case "forum":
@@ -160,61 +399,436 @@ func (u *PostUseCaseImpl) GetDetails(ctx context.Context, id int64, related []st
 
 			forum, err := u.forumRepo.GetBySlug(ctx, post.Forum)
 			if err != nil {
-				return postFull, err
+				return postFull, "", err
 			}
 
 			forumModel := forum.ToModel()
 			postFull.Forum = &forumModel
+			tags = append(tags, forum.ETag())
 
 			forumObtained = true
+		case "revisions":
+			if revisionsObtained {
+				break
+			}
+
+			revisions, err := u.postRepo.CountRevisions(ctx, post.Id)
+			if err != nil {
+				return postFull, "", err
+			}
+
+			postFull.Revisions = &revisions
+			tags = append(tags, "revisions", strconv.FormatInt(int64(revisions), 10))
+
+			revisionsObtained = true
+		case "ancestors":
+			if ancestorsObtained {
+				break
+			}
+
+			ancestors, err := u.getAncestors(ctx, post.Id, privileged)
+			if err != nil {
+				return postFull, "", err
+			}
+
+			postFull.Ancestors = ancestors
+			tags = append(tags, "ancestors")
+			for _, ancestor := range ancestors {
+				tags = append(tags, renderedETag(ancestor))
+			}
+
+			ancestorsObtained = true
 		case "":
 			// skips...
 		default:
 			log.Errorf("unexpected related entity: %s", entity)
-			return postFull, fmt.Errorf("unexpected related entity: %s", entity)
+			return postFull, "", fmt.Errorf("unexpected related entity: %s", entity)
 		}
 	}
 
-	return postFull, nil
+	if len(tags) > 1 {
+		tag = etag.OfFields(tags...)
+	}
+
+	return postFull, tag, nil
+}
+
+// renderedETag tags a post by what it was rendered to, for posts whose
+// entity is no longer at hand.
+func renderedETag(post models.Post) string {
+	var state string
+	if post.State != nil {
+		state = *post.State
+	}
+	return etag.OfFields("rendered post", strconv.FormatInt(*post.Id, 10), post.Author, post.Message, state)
 }
 
-func (u *PostUseCaseImpl) GetFromThread(ctx context.Context, thread string, since int64, limit uint64, desc bool, sort string) (models.Posts, error) {
+func (u *PostUseCaseImpl) GetAncestors(ctx context.Context, id int64) (_ models.Posts, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetAncestors")
+	defer tracing.End(span, &err)
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	privileged, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.getAncestors(ctx, id, privileged)
+}
+
+func (u *PostUseCaseImpl) getAncestors(ctx context.Context, id int64, privileged bool) (models.Posts, error) {
+	ancestors, err := u.postRepo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	obtained := make(models.Posts, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		obtained = append(obtained, present(ancestor, privileged))
+	}
+
+	return obtained, nil
+}
+
+// GetReplies returns a page of the descendants of a post along with the
+// cursor of the following page, if there is one.
+func (u *PostUseCaseImpl) GetReplies(ctx context.Context, id int64, depth int32, limit uint64, sort string, after string) (_ models.PostReplies, _ *string, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetReplies")
+	defer tracing.End(span, &err)
+
+	if depth < 0 {
+		return nil, nil, forumErrors.NewInvalidArgumentError("depth", strconv.Itoa(int(depth)))
+	}
+
+	var position *domain.Cursor
+	if after != "" {
+		position = &domain.Cursor{}
+		if err := cursor.Decode(after, position); err != nil {
+			return nil, nil, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		if sort != "" && sort != position.Sort {
+			return nil, nil, forumErrors.NewInvalidArgumentError("sort", sort)
+		}
+		if position.Sort != domain.SortFlat && len(position.Path) == 0 {
+			return nil, nil, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		sort = position.Sort
+	}
+
+	switch sort {
+	case "":
+		sort = domain.SortTree
+	case domain.SortTree, domain.SortFlat:
+	default:
+		return nil, nil, forumErrors.NewInvalidArgumentError("sort", sort)
+	}
+
+	if limit == 0 {
+		limit = defaultRepliesLimit
+	}
+	if limit > maxRepliesLimit {
+		limit = maxRepliesLimit
+	}
+
+	post, err := u.postRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privileged, err := identity.CanModerate(ctx, u.forumRepo, post.Forum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replies, err := u.postRepo.GetReplies(ctx, id, depth, position, cursor.FetchLimit(limit), sort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *string
+	if cursor.HasMore(len(replies), limit) {
+		replies = replies[:limit]
+
+		encoded, err := cursor.Encode(replies[limit-1].Cursor(sort, false))
+		if err != nil {
+			return nil, nil, err
+		}
+		next = &encoded
+	}
+
+	// Every returned reply is an expanded descendant of each of its returned
+	// ancestors; whatever is left of their descendants was cut off by depth
+	// or the page.
+	parents := make(map[int64]int64, len(replies))
+	for _, reply := range replies {
+		parents[reply.Id] = reply.Parent
+	}
+
+	expanded := make(map[int64]int64, len(replies))
+	for _, reply := range replies {
+		ancestor := reply.Parent
+		for {
+			parent, ok := parents[ancestor]
+			if !ok {
+				break
+			}
+			expanded[ancestor]++
+			ancestor = parent
+		}
+	}
+
+	obtained := make(models.PostReplies, 0, len(replies))
+	for _, reply := range replies {
+		obtained = append(obtained, models.PostReply{
+			Post:       present(reply.Post, privileged),
+			Unexpanded: reply.Descendants - expanded[reply.Id],
+		})
+	}
+
+	return obtained, next, nil
+}
+
+// threadPage is a page of thread posts before presentation. truncated
+// holds the ids of posts whose subtrees the page boundaries cut off: on an
+// ascending tree page, the ancestors of the first post past the page, on a
+// descending one, the ancestors of the last post before it.
+type threadPage struct {
+	posts      []domain.Post
+	truncated  []int64
+	nextCursor *string
+	total      int64
+}
+
+func (u *PostUseCaseImpl) GetFromThread(ctx context.Context, thread string, since int64, after string, limit uint64, desc *bool, sort string, withTotal bool) (_ models.PostsPage, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetFromThread")
+	defer tracing.End(span, &err)
+
+	page, err := u.getFromThread(ctx, thread, since, after, limit, desc, sort, withTotal, false)
+	if err != nil {
+		return models.PostsPage{}, err
+	}
+
+	obtained := make(models.Posts, 0, len(page.posts))
+	for _, post := range page.posts {
+		obtained = append(obtained, present(post, false))
+	}
+
+	return models.PostsPage{
+		Items:      obtained,
+		HasMore:    page.nextCursor != nil,
+		Total:      page.total,
+		NextCursor: page.nextCursor,
+	}, nil
+}
+
+func (u *PostUseCaseImpl) GetNestedFromThread(ctx context.Context, thread string, since int64, after string, limit uint64, desc *bool, sort string, withTotal bool) (_ models.PostNodesPage, err error) {
+	ctx, span := tracing.Start(ctx, "PostUseCase.GetNestedFromThread")
+	defer tracing.End(span, &err)
+
+	page, err := u.getFromThread(ctx, thread, since, after, limit, desc, sort, withTotal, true)
+	if err != nil {
+		return models.PostNodesPage{}, err
+	}
+
+	return models.PostNodesPage{
+		Items:      nest(page.posts, page.truncated),
+		HasMore:    page.nextCursor != nil,
+		Total:      page.total,
+		NextCursor: page.nextCursor,
+	}, nil
+}
+
+// getFromThread reads a page of thread posts. Nested pages need a tree
+// ordering, which is checked before anything is read.
+func (u *PostUseCaseImpl) getFromThread(ctx context.Context, thread string, since int64, after string, limit uint64, desc *bool, sort string, withTotal bool, nested bool) (threadPage, error) {
+	page := threadPage{}
+
 	var (
-		posts []domain.Post
-		err   error
+		posts    []domain.Post
+		position *domain.Cursor
+		err      error
 	)
 
+	descending := desc != nil && *desc
+
+	// A cursor carries the ordering it was made for; asking for another
+	// one along with it is a mistake rather than a new query.
+	if after != "" {
+		position = &domain.Cursor{}
+		if err = cursor.Decode(after, position); err != nil {
+			return page, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		if sort != "" && sort != position.Sort {
+			return page, forumErrors.NewInvalidArgumentError("sort", sort)
+		}
+		if desc != nil && *desc != position.Desc {
+			return page, forumErrors.NewInvalidArgumentError("desc", strconv.FormatBool(*desc))
+		}
+		if position.Sort != domain.SortFlat && len(position.Path) == 0 {
+			return page, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+		sort, descending = position.Sort, position.Desc
+	}
+
+	if sort != domain.SortTree && sort != domain.SortParentTree {
+		if nested {
+			return page, forumErrors.NewInvalidArgumentError("sort", sort)
+		}
+		sort = domain.SortFlat
+	}
+
 	threadId, err := strconv.ParseInt(thread, 10, 64)
 
+	var obtainedThread threadsDomain.Thread
 	if err != nil {
-		_, err = u.threadRepo.GetBySlug(ctx, thread)
+		obtainedThread, err = u.threadRepo.GetBySlug(ctx, thread)
 	} else {
-		_, err = u.threadRepo.GetById(ctx, threadId)
+		obtainedThread, err = u.threadRepo.GetById(ctx, threadId)
 	}
 
 	if err != nil {
-		return nil, err
+		return page, err
 	}
 
 	switch sort {
-	case "tree":
-		posts, err = u.postRepo.GetFromThreadTree(ctx, thread, since, limit, desc)
-	case "parent_tree":
-		posts, err = u.postRepo.GetFromThreadParentTree(ctx, thread, since, limit, desc)
-	case "flat":
-		fallthrough
+	case domain.SortTree:
+		posts, err = u.postRepo.GetFromThreadTree(ctx, thread, since, position, cursor.FetchLimit(limit), descending)
+	case domain.SortParentTree:
+		posts, err = u.postRepo.GetFromThreadParentTree(ctx, thread, since, position, cursor.FetchLimit(limit), descending)
 	default:
-		posts, err = u.postRepo.GetFromThreadFlat(ctx, thread, since, limit, desc)
+		posts, err = u.postRepo.GetFromThreadFlat(ctx, thread, since, position, cursor.FetchLimit(limit), descending)
 	}
 
 	if err != nil {
-		return nil, err
+		return page, err
+	}
+
+	if sort == domain.SortTree && descending && position != nil {
+		page.truncated = ancestors(position.Path)
+	}
+
+	var last *domain.Post
+	if sort == domain.SortParentTree {
+		posts, last = trimTrees(posts, limit)
+	} else if cursor.HasMore(len(posts), limit) {
+		if sort == domain.SortTree && !descending {
+			page.truncated = ancestors(posts[limit].Path)
+		}
+		posts = posts[:limit]
+		last = &posts[limit-1]
+	}
+
+	if last != nil {
+		next, err := cursor.Encode(last.Cursor(sort, descending))
+		if err != nil {
+			return page, err
+		}
+		page.nextCursor = &next
+	}
+
+	page.posts = posts
+
+	// The total counts what limit counts: root posts for parent_tree.
+	if withTotal {
+		page.total, err = u.postRepo.CountInThread(ctx, obtainedThread.Id, sort == domain.SortParentTree)
+	}
+
+	return page, err
+}
+
+// ancestors returns the ids on path above the post it leads to.
+func ancestors(path []int64) []int64 {
+	if len(path) == 0 {
+		return nil
+	}
+	return path[:len(path)-1]
+}
+
+// nest rebuilds the hierarchy of a tree page. Posts whose parent is not on
+// the page, as happens on pages after the first, become top level nodes.
+// Those of the truncated posts that are on the page are marked.
+func nest(posts []domain.Post, truncated []int64) models.PostNodes {
+	type node struct {
+		post      domain.Post
+		children  []*node
+		truncated bool
 	}
 
-	obtained := make(models.Posts, 0, len(posts))
+	nodes := make(map[int64]*node, len(posts))
 	for _, post := range posts {
-		obtained = append(obtained, post.ToModel())
+		nodes[post.Id] = &node{post: post}
 	}
 
-	return obtained, nil
+	top := make([]*node, 0)
+	for _, post := range posts {
+		if parent, ok := nodes[post.Parent]; ok {
+			parent.children = append(parent.children, nodes[post.Id])
+		} else {
+			top = append(top, nodes[post.Id])
+		}
+	}
+
+	for _, id := range truncated {
+		if n, ok := nodes[id]; ok {
+			n.truncated = true
+		}
+	}
+
+	var convert func(level []*node) models.PostNodes
+	convert = func(level []*node) models.PostNodes {
+		converted := make(models.PostNodes, 0, len(level))
+		for _, n := range level {
+			converted = append(converted, models.PostNode{
+				Post:      present(n.post, false),
+				Depth:     n.post.Depth,
+				Children:  convert(n.children),
+				Truncated: n.truncated,
+			})
+		}
+		return converted
+	}
+
+	return convert(top)
+}
+
+// trimTrees cuts parent tree pages, where the limit counts root posts, back
+// to limit trees and returns the root of the last kept tree if more follow.
+func trimTrees(posts []domain.Post, limit uint64) ([]domain.Post, *domain.Post) {
+	if limit == 0 {
+		return posts, nil
+	}
+
+	var (
+		roots uint64
+		last  *domain.Post
+	)
+
+	for idx := range posts {
+		if posts[idx].Parent != 0 {
+			continue
+		}
+		if roots == limit {
+			return posts[:idx], last
+		}
+		roots++
+		last = &posts[idx]
+	}
+
+	return posts, nil
+}
+
+func present(post domain.Post, privileged bool) models.Post {
+	if privileged {
+		return post.ToModelWithState()
+	}
+
+	if !post.IsVisible() {
+		return post.Tombstone().ToModelWithState()
+	}
+
+	return post.ToModel()
 }
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"github.com/rflban/parkmail-dbms/internal/forum/posts/domain"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// posts builds posts from "id:parent" pairs.
+func posts(pairs ...string) []domain.Post {
+	built := make([]domain.Post, 0, len(pairs))
+	for _, pair := range pairs {
+		ids := strings.Split(pair, ":")
+		id, _ := strconv.ParseInt(ids[0], 10, 64)
+		parent, _ := strconv.ParseInt(ids[1], 10, 64)
+		built = append(built, domain.Post{Id: id, Parent: parent})
+	}
+	return built
+}
+
+func ids(posts []domain.Post) []int64 {
+	collected := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		collected = append(collected, post.Id)
+	}
+	return collected
+}
+
+func TestTrimTrees(t *testing.T) {
+	tests := []struct {
+		name  string
+		posts []domain.Post
+		limit uint64
+		want  []int64
+		last  int64
+	}{
+		{name: "no limit", posts: posts("1:0", "2:1", "3:0"), limit: 0, want: []int64{1, 2, 3}},
+		{name: "empty", posts: posts(), limit: 2, want: []int64{}},
+		{name: "fewer trees than limit", posts: posts("1:0", "2:1"), limit: 2, want: []int64{1, 2}},
+		{name: "exactly limit trees", posts: posts("1:0", "2:1", "3:0", "4:3"), limit: 2, want: []int64{1, 2, 3, 4}},
+		{name: "one tree more", posts: posts("1:0", "2:1", "3:0", "4:3", "5:0"), limit: 2, want: []int64{1, 2, 3, 4}, last: 3},
+		{name: "deep tree kept whole", posts: posts("1:0", "2:1", "3:2", "4:3", "5:0"), limit: 1, want: []int64{1, 2, 3, 4}, last: 1},
+		{name: "page starting inside a tree", posts: posts("2:1", "3:2", "4:0", "5:0"), limit: 1, want: []int64{2, 3, 4}, last: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, last := trimTrees(tt.posts, tt.limit)
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Errorf("trimTrees() kept %v, want %v", ids(got), tt.want)
+			}
+
+			var lastId int64
+			if last != nil {
+				lastId = last.Id
+			}
+			if lastId != tt.last {
+				t.Errorf("trimTrees() last root = %d, want %d", lastId, tt.last)
+			}
+		})
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	tests := []struct {
+		name string
+		path []int64
+		want []int64
+	}{
+		{name: "no path", path: nil, want: nil},
+		{name: "root", path: []int64{1}, want: []int64{}},
+		{name: "nested", path: []int64{1, 4, 9}, want: []int64{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ancestors(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ancestors(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// shape renders nodes as "id(children)", marking truncated nodes with "*".
+func shape(nodes models.PostNodes) string {
+	rendered := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		item := strconv.FormatInt(*node.Id, 10)
+		if node.Truncated {
+			item += "*"
+		}
+		if len(node.Children) > 0 {
+			item += "(" + shape(node.Children) + ")"
+		}
+		rendered = append(rendered, item)
+	}
+	return strings.Join(rendered, " ")
+}
+
+func TestNest(t *testing.T) {
+	tests := []struct {
+		name      string
+		posts     []domain.Post
+		truncated []int64
+		want      string
+	}{
+		{name: "empty", posts: posts(), want: ""},
+		{name: "roots", posts: posts("1:0", "2:0"), want: "1 2"},
+		{name: "tree", posts: posts("1:0", "2:1", "3:2", "4:1", "5:0"), want: "1(2(3) 4) 5"},
+		{name: "parents off the page", posts: posts("3:2", "4:1", "6:4", "5:0"), want: "3 4(6) 5"},
+		{name: "truncated on the page", posts: posts("1:0", "2:1", "3:2"), truncated: []int64{1, 2}, want: "1*(2*(3))"},
+		{name: "truncated off the page", posts: posts("3:2", "5:0"), truncated: []int64{1, 2}, want: "3 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shape(nest(tt.posts, tt.truncated)); got != tt.want {
+				t.Errorf("nest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
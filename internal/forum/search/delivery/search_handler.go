@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"time"
+)
+
+type SearchUseCase interface {
+	Search(ctx context.Context, text, forum, author string, since *time.Time, limit uint64, after string) (models.SearchResults, error)
+}
+
+type SearchHandler struct {
+	searchUseCase SearchUseCase
+}
+
+func New(searchUseCase SearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		searchUseCase: searchUseCase,
+	}
+}
+
+func (h *SearchHandler) Search(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	text := string(rctx.QueryArgs().Peek("q"))
+	forum := string(rctx.QueryArgs().Peek("forum"))
+	author := string(rctx.QueryArgs().Peek("author"))
+	after := string(rctx.QueryArgs().Peek("cursor"))
+	limit, _ := strconv.ParseUint(string(rctx.QueryArgs().Peek("limit")), 10, 64)
+
+	var since *time.Time
+	if sinceRaw := rctx.QueryArgs().Peek("since"); len(sinceRaw) != 0 {
+		parsed, err := time.Parse(time.RFC3339, string(sinceRaw))
+		if err != nil {
+			log.Error(err.Error())
+
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid since",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+		since = &parsed
+	}
+
+	obtained, err := h.searchUseCase.Search(ctx, text, forum, author, since, limit, after)
+	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid search query or cursor",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"time"
+)
+
+const (
+	KindThread = "thread"
+	KindPost   = "post"
+)
+
+type Cursor struct {
+	Rank float32 `json:"r"`
+	Kind string  `json:"k"`
+	Id   int64   `json:"i"`
+}
+
+type Query struct {
+	Text   string
+	Forum  string
+	Author string
+	Since  *time.Time
+	Limit  uint64
+	After  *Cursor
+}
+
+type Result struct {
+	Kind    string
+	Id      int64
+	Thread  int64
+	Forum   string
+	Author  string
+	Title   *string
+	Snippet string
+	Rank    float32
+	Created time.Time
+}
+
+func (result Result) Cursor() Cursor {
+	return Cursor{
+		Rank: result.Rank,
+		Kind: result.Kind,
+		Id:   result.Id,
+	}
+}
+
+func (result Result) ToModel() models.SearchResult {
+	return models.SearchResult{
+		Type:    result.Kind,
+		Id:      result.Id,
+		Thread:  result.Thread,
+		Forum:   result.Forum,
+		Author:  result.Author,
+		Title:   result.Title,
+		Snippet: result.Snippet,
+		Rank:    result.Rank,
+		Created: &result.Created,
+	}
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rflban/parkmail-dbms/internal/forum/search/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tsQuery         = `websearch_to_tsquery('simple', ?)`
+	headlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10`
+)
+
+type SearchRepositoryPostgres struct {
+	db *pgxhook.Pool
+}
+
+func New(db *pgxhook.Pool) *SearchRepositoryPostgres {
+	return &SearchRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *SearchRepositoryPostgres) Search(ctx context.Context, query domain.Query) ([]domain.Result, error) {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Search",
+		"method": "Search",
+	})
+
+	threadsBuilder := sq.Select(
+		"'"+domain.KindThread+"' AS kind",
+		"id",
+		"id AS thread",
+		"forum",
+		"author",
+		"title",
+		"message AS body",
+	).
+		Column(sq.Expr("ts_rank(search, "+tsQuery+") AS rank", query.Text)).
+		Column("created").
+		From("threads").
+		Where("search @@ "+tsQuery, query.Text)
+	threadsBuilder = applyFilters(threadsBuilder, query)
+
+	postsBuilder := sq.Select(
+		"'"+domain.KindPost+"' AS kind",
+		"id",
+		"thread",
+		"forum",
+		"author",
+		"NULL AS title",
+		"message AS body",
+	).
+		Column(sq.Expr("ts_rank(search, "+tsQuery+") AS rank", query.Text)).
+		Column("created").
+		From("posts").
+		Where("search @@ "+tsQuery, query.Text).
+		Where("state = 'visible'")
+	postsBuilder = applyFilters(postsBuilder, query)
+
+	threadsQuery, threadsArgs, err := threadsBuilder.ToSql()
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	postsQuery, postsArgs, err := postsBuilder.ToSql()
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	unionArgs := make([]interface{}, 0, len(threadsArgs)+len(postsArgs))
+	unionArgs = append(unionArgs, threadsArgs...)
+	unionArgs = append(unionArgs, postsArgs...)
+
+	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("kind", "id", "thread", "forum", "author", "title").
+		Column(sq.Expr("ts_headline('simple', body, "+tsQuery+", '"+headlineOptions+"')", query.Text)).
+		Columns("rank", "created").
+		Prefix("WITH results AS ("+threadsQuery+" UNION ALL "+postsQuery+")", unionArgs...).
+		From("results")
+
+	if query.After != nil {
+		queryBuilder = queryBuilder.Where(
+			"(rank, kind, id) < (?::real, ?, ?)",
+			query.After.Rank,
+			query.After.Kind,
+			query.After.Id,
+		)
+	}
+
+	queryBuilder = queryBuilder.OrderBy("rank DESC", "kind DESC", "id DESC")
+
+	if query.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(query.Limit)
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql+";", args...)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.Result, 0)
+	for rows.Next() {
+		var result domain.Result
+
+		err = rows.Scan(
+			&result.Kind,
+			&result.Id,
+			&result.Thread,
+			&result.Forum,
+			&result.Author,
+			&result.Title,
+			&result.Snippet,
+			&result.Rank,
+			&result.Created,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+func applyFilters(queryBuilder sq.SelectBuilder, query domain.Query) sq.SelectBuilder {
+	if query.Forum != "" {
+		queryBuilder = queryBuilder.Where("forum = ?", query.Forum)
+	}
+	if query.Author != "" {
+		queryBuilder = queryBuilder.Where("author = ?", query.Author)
+	}
+	if query.Since != nil {
+		queryBuilder = queryBuilder.Where("created >= ?", *query.Since)
+	}
+	return queryBuilder
+}
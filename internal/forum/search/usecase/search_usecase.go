@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"github.com/rflban/parkmail-dbms/internal/forum/search/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, query domain.Query) ([]domain.Result, error)
+}
+
+type SearchUseCaseImpl struct {
+	searchRepo SearchRepository
+}
+
+func New(searchRepo SearchRepository) *SearchUseCaseImpl {
+	return &SearchUseCaseImpl{
+		searchRepo: searchRepo,
+	}
+}
+
+func (u *SearchUseCaseImpl) Search(ctx context.Context, text, forum, author string, since *time.Time, limit uint64, after string) (_ models.SearchResults, err error) {
+	ctx, span := tracing.Start(ctx, "SearchUseCase.Search")
+	defer tracing.End(span, &err)
+
+	searchResults := models.SearchResults{
+		Items: make([]models.SearchResult, 0),
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return searchResults, forumErrors.NewInvalidArgumentError("q", text)
+	}
+
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	query := domain.Query{
+		Text:   text,
+		Forum:  forum,
+		Author: author,
+		Since:  since,
+		Limit:  limit + 1,
+	}
+
+	if after != "" {
+		query.After = &domain.Cursor{}
+		if err := cursor.Decode(after, query.After); err != nil {
+			return searchResults, forumErrors.NewInvalidArgumentError("cursor", after)
+		}
+	}
+
+	results, err := u.searchRepo.Search(ctx, query)
+	if err != nil {
+		return searchResults, err
+	}
+
+	hasMore := uint64(len(results)) > limit
+	if hasMore {
+		results = results[:limit]
+	}
+
+	for _, result := range results {
+		searchResults.Items = append(searchResults.Items, result.ToModel())
+	}
+
+	if hasMore {
+		next, err := cursor.Encode(results[len(results)-1].Cursor())
+		if err != nil {
+			return searchResults, err
+		}
+		searchResults.NextCursor = &next
+	}
+
+	return searchResults, nil
+}
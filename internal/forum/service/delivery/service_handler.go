@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -11,7 +13,9 @@ import (
 
 type ServiceUseCase interface {
 	Status(ctx context.Context) (models.Status, error)
-	Clear(ctx context.Context) error
+	Clear(ctx context.Context, scope string, forum string) error
+	Ready(ctx context.Context) error
+	SlowQueries(ctx context.Context) (models.SlowQueries, error)
 }
 
 type ServiceHandler struct {
@@ -31,7 +35,8 @@ func (h *ServiceHandler) Status(rctx *fasthttp.RequestCtx) {
 	status, err := h.serviceUseCase.Status(ctx)
 	if err != nil {
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -44,7 +49,8 @@ func (h *ServiceHandler) Status(rctx *fasthttp.RequestCtx) {
 		log.Error(err)
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -60,10 +66,47 @@ func (h *ServiceHandler) Status(rctx *fasthttp.RequestCtx) {
 func (h *ServiceHandler) Clear(rctx *fasthttp.RequestCtx) {
 	ctx := rctx.UserValue("ctx").(context.Context)
 
-	err := h.serviceUseCase.Clear(ctx)
+	scope := string(rctx.QueryArgs().Peek("scope"))
+	forum := string(rctx.QueryArgs().Peek("forum"))
+
+	err := h.serviceUseCase.Clear(ctx, scope, forum)
 	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid clear scope",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "forum not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only admins can clear the service",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -73,3 +116,109 @@ func (h *ServiceHandler) Clear(rctx *fasthttp.RequestCtx) {
 
 	rctx.SetStatusCode(fasthttp.StatusOK)
 }
+
+// Health answers as long as the process can serve requests at all.
+func (h *ServiceHandler) Health(rctx *fasthttp.RequestCtx) {
+	body, _ := json.Marshal(models.Health{
+		Status: "ok",
+	})
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetContentType("application/json")
+	rctx.SetBody(body)
+}
+
+func (h *ServiceHandler) Ready(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	if err := h.serviceUseCase.Ready(ctx); err != nil {
+		if _, ok := err.(forumErrors.UnavailableError); ok {
+			log.Warn(err.Error())
+
+			body, _ := json.Marshal(models.Error{
+				Message:   err.Error(),
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, _ := json.Marshal(models.Health{
+		Status: "ready",
+	})
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *ServiceHandler) SlowQueries(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slowQueries, err := h.serviceUseCase.SlowQueries(ctx)
+	if err != nil {
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only admins can read slow queries",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(slowQueries)
+	if err != nil {
+		log.Error(err)
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
@@ -0,0 +1,15 @@
+package domain
+
+const (
+	ClearScopeAll   = "all"
+	ClearScopeVotes = "votes"
+)
+
+func IsValidClearScope(scope string) bool {
+	switch scope {
+	case ClearScopeAll, ClearScopeVotes:
+		return true
+	default:
+		return false
+	}
+}
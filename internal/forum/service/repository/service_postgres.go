@@ -2,9 +2,10 @@ package repositories
 
 import (
 	"context"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/service/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,14 +16,20 @@ const (
 		   (SELECT COUNT(*) FROM threads),
 		   (SELECT COUNT(*) FROM posts)
 		;`
-	queryTruncateAll = `TRUNCATE TABLE users, forums, forums_users, threads, posts, votes CASCADE;`
+	queryTruncateAll      = `TRUNCATE TABLE users, forums, forums_users, threads, posts, votes, idempotency_keys CASCADE;`
+	queryDeleteForum      = `DELETE FROM forums WHERE slug = $1;`
+	queryTruncateVotes    = `TRUNCATE TABLE votes;`
+	queryResetVotes       = `UPDATE threads SET votes = 0 WHERE votes <> 0;`
+	queryDeleteForumVotes = `DELETE FROM votes WHERE thread IN (SELECT id FROM threads WHERE forum = $1);`
+	queryResetForumVotes  = `UPDATE threads SET votes = 0 WHERE forum = $1 AND votes <> 0;`
+	queryForumExists      = `SELECT EXISTS (SELECT 1 FROM forums WHERE slug = $1);`
 )
 
 type ServiceRepoPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *ServiceRepoPostgres {
+func New(db *pgxhook.Pool) *ServiceRepoPostgres {
 	return &ServiceRepoPostgres{
 		db: db,
 	}
@@ -63,3 +70,101 @@ func (r *ServiceRepoPostgres) Clear(ctx context.Context) error {
 
 	return err
 }
+
+func (r *ServiceRepoPostgres) ClearForum(ctx context.Context, slug string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Service",
+		"method": "ClearForum",
+	})
+
+	tag, err := r.db.Exec(ctx, queryDeleteForum, slug)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("forums")
+	}
+
+	return nil
+}
+
+func (r *ServiceRepoPostgres) ClearVotes(ctx context.Context) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Service",
+		"method": "ClearVotes",
+	})
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	_, err = tx.Exec(ctx, queryTruncateVotes)
+	if err == nil {
+		_, err = tx.Exec(ctx, queryResetVotes)
+	}
+
+	if err != nil {
+		log.Error(err.Error())
+
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ServiceRepoPostgres) ClearForumVotes(ctx context.Context, slug string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Service",
+		"method": "ClearForumVotes",
+	})
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, queryForumExists, slug).Scan(&exists)
+	if err == nil && !exists {
+		err = forumErrors.NewEntityNotExistsError("forums")
+	}
+	if err == nil {
+		_, err = tx.Exec(ctx, queryDeleteForumVotes, slug)
+	}
+	if err == nil {
+		_, err = tx.Exec(ctx, queryResetForumVotes, slug)
+	}
+
+	if err != nil {
+		log.Error(err.Error())
+
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ServiceRepoPostgres) Ping(ctx context.Context) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Service",
+		"method": "Ping",
+	})
+
+	err := r.db.Ping(ctx)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return err
+}
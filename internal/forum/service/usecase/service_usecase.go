@@ -2,31 +2,153 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"github.com/rflban/parkmail-dbms/internal/forum/service/domain"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/migrations"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/slowlog"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"sync/atomic"
+	"time"
 )
 
 type ServiceRepository interface {
 	Status(ctx context.Context) (domain.Status, error)
 	Clear(ctx context.Context) error
+	ClearForum(ctx context.Context, slug string) error
+	ClearVotes(ctx context.Context) error
+	ClearForumVotes(ctx context.Context, slug string) error
+	Ping(ctx context.Context) error
+}
+
+type MigrationStatus interface {
+	Status(ctx context.Context) ([]migrations.State, error)
+}
+
+type SlowQueryLog interface {
+	Recent() []slowlog.Entry
 }
 
 type ServiceUseCaseImpl struct {
-	serviceRepo ServiceRepository
+	serviceRepo  ServiceRepository
+	migrator     MigrationStatus
+	slowQueries  SlowQueryLog
+	clearEnabled bool
+	draining     int32
 }
 
-func New(serviceRepo ServiceRepository) *ServiceUseCaseImpl {
+func New(serviceRepo ServiceRepository, migrator MigrationStatus, slowQueries SlowQueryLog, clearEnabled bool) *ServiceUseCaseImpl {
 	return &ServiceUseCaseImpl{
-		serviceRepo: serviceRepo,
+		serviceRepo:  serviceRepo,
+		migrator:     migrator,
+		slowQueries:  slowQueries,
+		clearEnabled: clearEnabled,
 	}
 }
 
-func (uc *ServiceUseCaseImpl) Status(ctx context.Context) (models.Status, error) {
+func (uc *ServiceUseCaseImpl) Status(ctx context.Context) (_ models.Status, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceUseCase.Status")
+	defer tracing.End(span, &err)
+
 	status, err := uc.serviceRepo.Status(ctx)
 	return status.ToModel(), err
 }
 
-func (uc *ServiceUseCaseImpl) Clear(ctx context.Context) error {
-	err := uc.serviceRepo.Clear(ctx)
-	return err
+func (uc *ServiceUseCaseImpl) Clear(ctx context.Context, scope string, forum string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceUseCase.Clear")
+	defer tracing.End(span, &err)
+
+	if scope == "" {
+		scope = domain.ClearScopeAll
+	}
+	if !domain.IsValidClearScope(scope) {
+		return forumErrors.NewInvalidArgumentError("scope", scope)
+	}
+
+	if !(uc.clearEnabled || identity.IsAdmin(ctx)) {
+		return forumErrors.NewForbiddenError("clear the service")
+	}
+
+	switch {
+	case scope == domain.ClearScopeVotes && forum != "":
+		return uc.serviceRepo.ClearForumVotes(ctx, forum)
+	case scope == domain.ClearScopeVotes:
+		return uc.serviceRepo.ClearVotes(ctx)
+	case forum != "":
+		return uc.serviceRepo.ClearForum(ctx, forum)
+	default:
+		return uc.serviceRepo.Clear(ctx)
+	}
+}
+
+// Drain marks the service as shutting down, so it stops reporting itself
+// ready while in-flight requests complete.
+func (uc *ServiceUseCaseImpl) Drain() {
+	atomic.StoreInt32(&uc.draining, 1)
+}
+
+// Ready reports whether the service can take traffic: it is not shutting
+// down, the database answers and every known migration has been applied
+// as it is. Migrations applied by a newer release are fine.
+func (uc *ServiceUseCaseImpl) Ready(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceUseCase.Ready")
+	defer tracing.End(span, &err)
+
+	if atomic.LoadInt32(&uc.draining) != 0 {
+		return forumErrors.NewUnavailableError("shutting down")
+	}
+
+	if err := uc.serviceRepo.Ping(ctx); err != nil {
+		return forumErrors.NewUnavailableError("database is unreachable")
+	}
+
+	states, err := uc.migrator.Status(ctx)
+	if err != nil {
+		return forumErrors.NewUnavailableError("migrations can not be read")
+	}
+
+	for _, state := range states {
+		if !state.Applied {
+			return forumErrors.NewUnavailableError(fmt.Sprintf("migration %04d_%s is pending", state.Version, state.Name))
+		}
+		if state.Modified {
+			return forumErrors.NewUnavailableError(fmt.Sprintf("migration %04d_%s was modified", state.Version, state.Name))
+		}
+	}
+
+	return nil
+}
+
+// SlowQueries lists the latest slow statements. They carry the arguments
+// of other users' requests, passwords and tokens among them, so only admins
+// may see them.
+func (uc *ServiceUseCaseImpl) SlowQueries(ctx context.Context) (_ models.SlowQueries, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceUseCase.SlowQueries")
+	defer tracing.End(span, &err)
+
+	if _, ok := identity.Caller(ctx); !ok {
+		return nil, forumErrors.NewUnauthorizedError("authentication required")
+	}
+	if !identity.IsAdmin(ctx) {
+		return nil, forumErrors.NewForbiddenError("read slow queries")
+	}
+
+	recent := uc.slowQueries.Recent()
+
+	slowQueries := make(models.SlowQueries, 0, len(recent))
+	for _, entry := range recent {
+		slowQueries = append(slowQueries, models.SlowQuery{
+			Id:         entry.Id,
+			Sql:        entry.Sql,
+			Args:       slowlog.FormatArgs(entry.Args),
+			DurationMs: float64(entry.Duration) / float64(time.Millisecond),
+			RequestId:  entry.RequestId,
+			Captured:   entry.Captured,
+			Plan:       entry.Plan,
+		})
+	}
+
+	return slowQueries, nil
 }
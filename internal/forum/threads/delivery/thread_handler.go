@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/cursor"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/envelope"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -13,13 +17,16 @@ import (
 
 type ThreadUseCase interface {
 	Create(ctx context.Context, thread models.Thread) (models.Thread, error)
-	GetBySlugOrId(ctx context.Context, slugOrId string) (models.Thread, error)
-	PatchBySlugOrId(ctx context.Context, slugOrId string, threadUpdate models.ThreadUpdate) (models.Thread, error)
+	GetBySlugOrId(ctx context.Context, slugOrId string) (models.Thread, string, error)
+	PatchBySlugOrId(ctx context.Context, slugOrId string, threadUpdate models.ThreadUpdate, ifMatch []byte) (models.Thread, string, error)
+	GetRevisionsBySlugOrId(ctx context.Context, slugOrId string) (models.ThreadRevisions, error)
+	DeleteBySlugOrId(ctx context.Context, slugOrId string) error
 }
 
 type PostUseCase interface {
-	Create(ctx context.Context, threadSlugOrId string, posts models.Posts) (models.Posts, error)
-	GetFromThread(ctx context.Context, thread string, since int64, limit uint64, desc bool, sort string) (models.Posts, error)
+	Create(ctx context.Context, threadSlugOrId string, posts models.Posts, partial bool) (models.PostsBatch, error)
+	GetFromThread(ctx context.Context, thread string, since int64, after string, limit uint64, desc *bool, sort string, withTotal bool) (models.PostsPage, error)
+	GetNestedFromThread(ctx context.Context, thread string, since int64, after string, limit uint64, desc *bool, sort string, withTotal bool) (models.PostNodesPage, error)
 }
 
 type VoteUseCase interface {
@@ -49,7 +56,8 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug_or_id",
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -62,7 +70,8 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -70,11 +79,48 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	obtained, err := h.postUseCase.Create(ctx, slugOrId, fromBody)
+	partial := string(rctx.QueryArgs().Peek("partial")) == "true"
+
+	obtained, err := h.postUseCase.Create(ctx, slugOrId, fromBody, partial)
 	if err != nil {
+		if batchErr, ok := err.(forumErrors.BatchError); ok {
+			batchBody := models.BatchError{
+				RequestId: middlewares.RequestIdFrom(ctx),
+				Errors:    make(models.PostErrors, 0, len(batchErr.Items)),
+			}
+			for _, item := range batchErr.Items {
+				batchBody.Errors = append(batchBody.Errors, models.PostError{
+					Index:   item.Index,
+					Code:    item.Code,
+					Message: item.Message,
+				})
+			}
+
+			authorUnknown := batchErr.Has(forumErrors.ItemAuthorUnknown)
+			parentNotInThread := batchErr.Has(forumErrors.ItemParentNotInThread)
+
+			status := fasthttp.StatusNotFound
+			switch {
+			case authorUnknown && parentNotInThread:
+				batchBody.Message = "author not found and parent post was created in another thread"
+			case authorUnknown:
+				batchBody.Message = "author not found"
+			default:
+				batchBody.Message = "Parent post was created in another thread"
+				status = fasthttp.StatusConflict
+			}
+
+			body, _ := json.Marshal(batchBody)
+
+			rctx.SetStatusCode(status)
+			rctx.SetBody(body)
+			return
+		}
+
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread or author not found",
+				Message:   "thread or author not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -84,7 +130,8 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 
 		if _, ok := err.(forumErrors.UniqueError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "conflict with another thread's data",
+				Message:   "conflict with another thread's data",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusConflict)
@@ -94,7 +141,8 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 
 		if _, ok := err.(forumErrors.ConflictError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "Parent post was created in another thread",
+				Message:   "Parent post was created in another thread",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusConflict)
@@ -102,8 +150,20 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not create posts on behalf of another user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -111,12 +171,18 @@ func (h *ThreadHandler) CreatePosts(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	body, err := json.Marshal(obtained)
+	var body []byte
+	if partial {
+		body, err = json.Marshal(obtained)
+	} else {
+		body, err = json.Marshal(obtained.Created)
+	}
 	if err != nil {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -137,7 +203,8 @@ func (h *ThreadHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug_or_id",
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -145,11 +212,12 @@ func (h *ThreadHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	obtained, err := h.threadUseCase.GetBySlugOrId(ctx, slugOrId)
+	obtained, tag, err := h.threadUseCase.GetBySlugOrId(ctx, slugOrId)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread not found",
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -158,7 +226,8 @@ func (h *ThreadHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -171,7 +240,8 @@ func (h *ThreadHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -179,6 +249,13 @@ func (h *ThreadHandler) GetDetails(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
+
+	if etag.Match(rctx.Request.Header.Peek(fasthttp.HeaderIfNoneMatch), tag) {
+		rctx.SetStatusCode(fasthttp.StatusNotModified)
+		return
+	}
+
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
@@ -192,7 +269,8 @@ func (h *ThreadHandler) Edit(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug_or_id",
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -205,7 +283,113 @@ func (h *ThreadHandler) Edit(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	obtained, tag, err := h.threadUseCase.PatchBySlugOrId(ctx, slugOrId, fromBody, rctx.Request.Header.Peek(fasthttp.HeaderIfMatch))
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ConflictError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "thread was modified since expected revision",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusConflict)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.PreconditionFailedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "thread was modified since it was fetched",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusPreconditionFailed)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the author or forum moderators can edit the thread",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	body, err := json.Marshal(obtained)
+	if err != nil {
+		log.Error(err.Error())
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
+	rctx.SetStatusCode(fasthttp.StatusOK)
+	rctx.SetBody(body)
+}
+
+func (h *ThreadHandler) GetRevisions(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slugOrId, ok := rctx.UserValue("slug_or_id").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -213,11 +397,12 @@ func (h *ThreadHandler) Edit(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	obtained, err := h.threadUseCase.PatchBySlugOrId(ctx, slugOrId, fromBody)
+	obtained, err := h.threadUseCase.GetRevisionsBySlugOrId(ctx, slugOrId)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread not found",
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -226,7 +411,8 @@ func (h *ThreadHandler) Edit(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -239,7 +425,8 @@ func (h *ThreadHandler) Edit(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -260,7 +447,8 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug_or_id",
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -274,7 +462,13 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 	descRaw := rctx.QueryArgs().Peek("desc")
 
 	sort := string(sortRaw)
-	desc := string(descRaw) == "true"
+	after := string(rctx.QueryArgs().Peek("cursor"))
+
+	var desc *bool
+	if rctx.QueryArgs().Has("desc") {
+		descParsed := string(descRaw) == "true"
+		desc = &descParsed
+	}
 	since, err := strconv.ParseInt(string(sinceRaw), 10, 64)
 	if err != nil {
 		since = 0
@@ -284,11 +478,39 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 		limit = 0
 	}
 
-	obtained, err := h.postUseCase.GetFromThread(ctx, slugOrId, since, limit, desc, sort)
+	wrap := envelope.Requested(rctx)
+
+	var (
+		items, whole interface{}
+		next         *string
+	)
+
+	switch string(rctx.QueryArgs().Peek("format")) {
+	case "nested":
+		var page models.PostNodesPage
+		page, err = h.postUseCase.GetNestedFromThread(ctx, slugOrId, since, after, limit, desc, sort, wrap)
+		items, whole, next = page.Items, page, page.NextCursor
+	default:
+		var page models.PostsPage
+		page, err = h.postUseCase.GetFromThread(ctx, slugOrId, since, after, limit, desc, sort, wrap)
+		items, whole, next = page.Items, page, page.NextCursor
+	}
+
 	if err != nil {
+		if _, ok := err.(forumErrors.InvalidArgumentError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "invalid cursor, sort or desc",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusBadRequest)
+			rctx.SetBody(body)
+			return
+		}
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread not found",
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -297,7 +519,8 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -305,12 +528,18 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	body, err := json.Marshal(obtained)
+	var body []byte
+	if wrap {
+		body, err = json.Marshal(whole)
+	} else {
+		body, err = json.Marshal(items)
+	}
 	if err != nil {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -318,6 +547,9 @@ func (h *ThreadHandler) GetPosts(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if next != nil {
+		rctx.Response.Header.Set(cursor.NextHeader, *next)
+	}
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
@@ -331,7 +563,8 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid slug_or_id",
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -344,7 +577,8 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -356,7 +590,8 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "thread or user not found",
+				Message:   "thread or user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -364,8 +599,20 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not vote on behalf of another user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -378,7 +625,8 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -389,3 +637,71 @@ func (h *ThreadHandler) Vote(rctx *fasthttp.RequestCtx) {
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
+
+func (h *ThreadHandler) Delete(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	slugOrId, ok := rctx.UserValue("slug_or_id").(string)
+	if !ok {
+		log.Errorf("Can't parse slug: %v", rctx.UserValue("slug_or_id"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid slug_or_id",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	err := h.threadUseCase.DeleteBySlugOrId(ctx, slugOrId)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "thread not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "only the author or forum moderators can delete the thread",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Del(fasthttp.HeaderContentType)
+	rctx.SetStatusCode(fasthttp.StatusNoContent)
+}
@@ -3,13 +3,17 @@ package domain
 import "github.com/rflban/parkmail-dbms/pkg/forum/models"
 
 type PartialThread struct {
-	Title   *string
-	Message *string
+	Title            *string
+	Message          *string
+	ExpectedRevision *int32
+	// IfMatch is checked against the ETag of the thread under its row lock.
+	IfMatch []byte
 }
 
 func FromModelUpdate(thread models.ThreadUpdate) PartialThread {
 	return PartialThread{
-		Title:   thread.Title,
-		Message: thread.Message,
+		Title:            thread.Title,
+		Message:          thread.Message,
+		ExpectedRevision: thread.ExpectedRevision,
 	}
 }
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+type Cursor struct {
+	Created time.Time `json:"c"`
+	Id      int64     `json:"i"`
+	Desc    bool      `json:"d,omitempty"`
+}
+
+func (thread Thread) Cursor(desc bool) Cursor {
+	return Cursor{
+		Created: thread.Created,
+		Id:      thread.Id,
+		Desc:    desc,
+	}
+}
@@ -1,33 +1,50 @@
 package domain
 
 import (
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"strconv"
 	"time"
 )
 
 type Thread struct {
-	Id      int64
-	Title   string
-	Author  string
-	Forum   string
-	Message string
-	Votes   int32
-	Slug    string
-	Created time.Time
+	Id       int64
+	Title    string
+	Author   string
+	Forum    string
+	Message  string
+	Votes    int32
+	Slug     string
+	Created  time.Time
+	Revision int32
+}
+
+// ETag follows the revision, which every edit of the title or message bumps,
+// and the votes, so that a read after a vote is not answered with 304.
+// Editors that only care about the title and message can send
+// expected_revision instead of If-Match.
+func (thread Thread) ETag() string {
+	return etag.OfFields(
+		"thread",
+		strconv.FormatInt(thread.Id, 10),
+		strconv.FormatInt(int64(thread.Revision), 10),
+		strconv.FormatInt(int64(thread.Votes), 10),
+	)
 }
 
 func (thread Thread) ToModel() models.Thread {
 	id := int32(thread.Id)
 
 	return models.Thread{
-		Id:      &id,
-		Title:   thread.Title,
-		Author:  thread.Author,
-		Forum:   &thread.Forum,
-		Message: thread.Message,
-		Votes:   &thread.Votes,
-		Slug:    &thread.Slug,
-		Created: &thread.Created,
+		Id:       &id,
+		Title:    thread.Title,
+		Author:   thread.Author,
+		Forum:    &thread.Forum,
+		Message:  thread.Message,
+		Votes:    &thread.Votes,
+		Slug:     &thread.Slug,
+		Created:  &thread.Created,
+		Revision: &thread.Revision,
 	}
 }
 
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"time"
+)
+
+type ThreadRevision struct {
+	Thread  int64
+	Number  int32
+	Author  string
+	Title   string
+	Message string
+	Created time.Time
+}
+
+func (revision ThreadRevision) ToModel() models.ThreadRevision {
+	return models.ThreadRevision{
+		Number:  revision.Number,
+		Author:  revision.Author,
+		Title:   revision.Title,
+		Message: revision.Message,
+		Created: &revision.Created,
+	}
+}
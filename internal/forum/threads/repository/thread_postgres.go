@@ -5,10 +5,11 @@ import (
 	"errors"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 	"time"
 )
@@ -16,31 +17,36 @@ import (
 const (
 	queryCreate = `INSERT INTO threads (title, author, forum, message, slug, created)
 					VALUES ($1, $2, $3, $4, $5, $6)
-					RETURNING id, title, author, forum, message, slug, created, votes;`
+					RETURNING id, title, author, forum, message, slug, created, votes, revision;`
 	queryCreate2 = `INSERT INTO threads (title, author, forum, message, slug)
 					VALUES ($1, $2, $3, $4, $5)
-					RETURNING id, title, author, forum, message, slug, created, votes;`
-	queryGetById    = `SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE id = $1;`
-	queryGetBySlug  = `SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE slug = $1;`
+					RETURNING id, title, author, forum, message, slug, created, votes, revision;`
+	queryGetById    = `SELECT id, title, author, forum, message, votes, slug, created, revision FROM threads WHERE id = $1;`
+	queryGetBySlug  = `SELECT id, title, author, forum, message, votes, slug, created, revision FROM threads WHERE slug = $1;`
 	queryUpdateById = `UPDATE threads SET
 						title = COALESCE(NULLIF(TRIM($2), ''), title),
-						message = COALESCE(NULLIF(TRIM($3), ''), message)
+						message = COALESCE(NULLIF(TRIM($3), ''), message),
+						revision = CASE
+							WHEN COALESCE(NULLIF(TRIM($2), ''), title) <> title
+								OR COALESCE(NULLIF(TRIM($3), ''), message) <> message
+							THEN revision + 1
+							ELSE revision
+						END
 						WHERE id = $1
-						RETURNING id, title, author, forum, message, votes, slug, created;`
-	queryUpdateBySlug = `
-							UPDATE threads
-							SET
-								 title = COALESCE(NULLIF(TRIM($2), ''), title),
-								 message = COALESCE(NULLIF(TRIM($3), ''), message)
-							WHERE slug = $1
-							RETURNING id, title, author, forum, message, votes, slug, created;`
+						RETURNING id, title, author, forum, message, votes, slug, created, revision;`
+	queryGetForUpdateById = `SELECT id, title, message, votes, revision FROM threads WHERE id = $1 FOR UPDATE;`
+	queryCreateRevision   = `
+					INSERT INTO thread_revisions (thread, number, author, title, message)
+					VALUES ($1, $2, COALESCE(NULLIF($3, ''), $4), $5, $6);`
+	queryGetRevisions = `SELECT number, author, title, message, created FROM thread_revisions WHERE thread = $1 ORDER BY number;`
+	queryDeleteById   = `DELETE FROM threads WHERE id = $1;`
 )
 
 type ThreadRepositoryPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *ThreadRepositoryPostgres {
+func New(db *pgxhook.Pool) *ThreadRepositoryPostgres {
 	return &ThreadRepositoryPostgres{
 		db: db,
 	}
@@ -93,6 +99,7 @@ func (r *ThreadRepositoryPostgres) Create(ctx context.Context, thread domain.Thr
 		&fetchedSlug,
 		&obtained.Created,
 		&obtained.Votes,
+		&obtained.Revision,
 	)
 
 	if fetchedSlug != nil {
@@ -139,6 +146,7 @@ func (r *ThreadRepositoryPostgres) GetById(ctx context.Context, id int64) (domai
 		&thread.Votes,
 		&slug,
 		&thread.Created,
+		&thread.Revision,
 	)
 
 	if slug != nil {
@@ -175,6 +183,7 @@ func (r *ThreadRepositoryPostgres) GetBySlug(ctx context.Context, slug string) (
 		&thread.Votes,
 		&fetchedSlug,
 		&thread.Created,
+		&thread.Revision,
 	)
 
 	if fetchedSlug != nil {
@@ -191,60 +200,134 @@ func (r *ThreadRepositoryPostgres) GetBySlug(ctx context.Context, slug string) (
 	return thread, err
 }
 
-func (r *ThreadRepositoryPostgres) Patch(ctx context.Context, id int64, partialThread domain.PartialThread) (domain.Thread, error) {
+func (r *ThreadRepositoryPostgres) Patch(ctx context.Context, id int64, partialThread domain.PartialThread, editor string) (domain.Thread, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Thread",
 		"method": "Patch",
 	})
 
-	var thread domain.Thread
+	var (
+		thread   domain.Thread
+		previous domain.Thread
+		slug     *string
+	)
 
-	err := r.db.QueryRow(ctx, queryUpdateById, id, partialThread.Title, partialThread.Message).Scan(
-		&thread.Id,
-		&thread.Title,
-		&thread.Author,
-		&thread.Forum,
-		&thread.Message,
-		&thread.Votes,
-		&thread.Slug,
-		&thread.Created,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return thread, err
+	}
+
+	err = tx.QueryRow(ctx, queryGetForUpdateById, id).Scan(
+		&previous.Id,
+		&previous.Title,
+		&previous.Message,
+		&previous.Votes,
+		&previous.Revision,
 	)
+	if err == nil && len(partialThread.IfMatch) != 0 && !etag.MatchStrong(partialThread.IfMatch, previous.ETag()) {
+		err = forumErrors.NewPreconditionFailedError("thread")
+	}
+	if err == nil && partialThread.ExpectedRevision != nil && *partialThread.ExpectedRevision != previous.Revision {
+		err = forumErrors.NewConflictError("thread was modified concurrently")
+	}
+	if err == nil {
+		err = tx.QueryRow(ctx, queryUpdateById, previous.Id, partialThread.Title, partialThread.Message).Scan(
+			&thread.Id,
+			&thread.Title,
+			&thread.Author,
+			&thread.Forum,
+			&thread.Message,
+			&thread.Votes,
+			&slug,
+			&thread.Created,
+			&thread.Revision,
+		)
+	}
+	if err == nil && thread.Revision != previous.Revision {
+		_, err = tx.Exec(ctx, queryCreateRevision,
+			thread.Id,
+			thread.Revision,
+			editor,
+			thread.Author,
+			previous.Title,
+			previous.Message,
+		)
+	}
+
+	if slug != nil {
+		thread.Slug = *slug
+	}
 
 	if err != nil {
 		log.Error(err.Error())
+
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+
 		if err.Error() == pgx.ErrNoRows.Error() {
 			return thread, forumErrors.NewEntityNotExistsError("threads")
 		}
+		return thread, err
 	}
 
+	err = tx.Commit(ctx)
+
 	return thread, err
 }
 
-func (r *ThreadRepositoryPostgres) PatchBySlug(ctx context.Context, slug string, partialThread domain.PartialThread) (domain.Thread, error) {
+func (r *ThreadRepositoryPostgres) GetRevisions(ctx context.Context, id int64) ([]domain.ThreadRevision, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Thread",
-		"method": "Patch",
+		"method": "GetRevisions",
 	})
 
-	var thread domain.Thread
+	rows, err := r.db.Query(ctx, queryGetRevisions, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
 
-	err := r.db.QueryRow(ctx, queryUpdateBySlug, slug, partialThread.Title, partialThread.Message).Scan(
-		&thread.Id,
-		&thread.Title,
-		&thread.Author,
-		&thread.Forum,
-		&thread.Message,
-		&thread.Votes,
-		&thread.Slug,
-		&thread.Created,
-	)
+	revisions := make([]domain.ThreadRevision, 0)
+	revision := domain.ThreadRevision{
+		Thread: id,
+	}
+
+	for rows.Next() {
+		err := rows.Scan(
+			&revision.Number,
+			&revision.Author,
+			&revision.Title,
+			&revision.Message,
+			&revision.Created,
+		)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (r *ThreadRepositoryPostgres) Delete(ctx context.Context, id int64) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "Thread",
+		"method": "Delete",
+	})
 
+	tag, err := r.db.Exec(ctx, queryDeleteById, id)
 	if err != nil {
 		log.Error(err.Error())
-		if err.Error() == pgx.ErrNoRows.Error() {
-			return thread, forumErrors.NewEntityNotExistsError("threads")
-		}
+		return err
 	}
 
-	return thread, err
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("threads")
+	}
+
+	return nil
 }
@@ -6,6 +6,9 @@ import (
 	"github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	usersDomain "github.com/rflban/parkmail-dbms/internal/forum/users/domain"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"strconv"
 )
@@ -14,12 +17,14 @@ type ThreadRepository interface {
 	Create(ctx context.Context, thread domain.Thread) (domain.Thread, error)
 	GetById(ctx context.Context, id int64) (domain.Thread, error)
 	GetBySlug(ctx context.Context, slug string) (domain.Thread, error)
-	Patch(ctx context.Context, id int64, partialThread domain.PartialThread) (domain.Thread, error)
-	PatchBySlug(ctx context.Context, slug string, partialThread domain.PartialThread) (domain.Thread, error)
+	Patch(ctx context.Context, id int64, partialThread domain.PartialThread, editor string) (domain.Thread, error)
+	GetRevisions(ctx context.Context, id int64) ([]domain.ThreadRevision, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type ForumRepository interface {
 	GetBySlug(ctx context.Context, slug string) (forumsDomain.Forum, error)
+	CanModerate(ctx context.Context, slug string, nickname string) (bool, error)
 }
 
 type UserRepository interface {
@@ -40,7 +45,14 @@ func New(threadRepo ThreadRepository, forumRepo ForumRepository, userRepo UserRe
 	}
 }
 
-func (u *ThreadUseCaseImpl) Create(ctx context.Context, thread models.Thread) (models.Thread, error) {
+func (u *ThreadUseCaseImpl) Create(ctx context.Context, thread models.Thread) (_ models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.Create")
+	defer tracing.End(span, &err)
+
+	if err := identity.ActAs(ctx, thread.Author); err != nil {
+		return thread, err
+	}
+
 	if thread.Slug != nil {
 		obtained, err := u.threadRepo.GetBySlug(ctx, *thread.Slug)
 		if err == nil {
@@ -62,47 +74,115 @@ func (u *ThreadUseCaseImpl) Create(ctx context.Context, thread models.Thread) (m
 	}
 
 	created, err := u.threadRepo.Create(ctx, domain.FromModel(thread, nil))
+	if err == nil {
+		metrics.ThreadCreated()
+	}
 	created.Forum = forum.Slug
 	created.Author = user.Nickname
 
 	return created.ToModel(), err
 }
 
-func (u *ThreadUseCaseImpl) GetById(ctx context.Context, id int64) (models.Thread, error) {
+func (u *ThreadUseCaseImpl) GetById(ctx context.Context, id int64) (_ models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.GetById")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.threadRepo.GetById(ctx, id)
 	return obtained.ToModel(), err
 }
 
-func (u *ThreadUseCaseImpl) GetBySlug(ctx context.Context, slug string) (models.Thread, error) {
+func (u *ThreadUseCaseImpl) GetBySlug(ctx context.Context, slug string) (_ models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.GetBySlug")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.threadRepo.GetBySlug(ctx, slug)
 	return obtained.ToModel(), err
 }
 
-func (u *ThreadUseCaseImpl) GetBySlugOrId(ctx context.Context, slugOrId string) (models.Thread, error) {
-	id, err := strconv.ParseInt(slugOrId, 10, 64)
+// GetBySlugOrId returns the thread along with its ETag.
+func (u *ThreadUseCaseImpl) GetBySlugOrId(ctx context.Context, slugOrId string) (_ models.Thread, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.GetBySlugOrId")
+	defer tracing.End(span, &err)
 
+	obtained, err := u.getBySlugOrId(ctx, slugOrId)
 	if err != nil {
-		obtained, err := u.threadRepo.GetBySlug(ctx, slugOrId)
-		return obtained.ToModel(), err
-	} else {
-		obtained, err := u.threadRepo.GetById(ctx, id)
-		return obtained.ToModel(), err
+		return models.Thread{}, "", err
 	}
+
+	return obtained.ToModel(), obtained.ETag(), nil
 }
 
-func (u *ThreadUseCaseImpl) Patch(ctx context.Context, id int64, threadUpdate models.ThreadUpdate) (models.Thread, error) {
-	edited, err := u.threadRepo.Patch(ctx, id, domain.FromModelUpdate(threadUpdate))
-	return edited.ToModel(), err
+// PatchBySlugOrId edits the thread and returns it with its new ETag. A
+// non-empty ifMatch makes the edit conditional on the current ETag.
+func (u *ThreadUseCaseImpl) PatchBySlugOrId(ctx context.Context, slugOrId string, threadUpdate models.ThreadUpdate, ifMatch []byte) (_ models.Thread, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.PatchBySlugOrId")
+	defer tracing.End(span, &err)
+
+	thread, err := u.getBySlugOrId(ctx, slugOrId)
+	if err != nil {
+		return models.Thread{}, "", err
+	}
+
+	if err := identity.Manage(ctx, u.forumRepo, thread.Forum, thread.Author); err != nil {
+		return models.Thread{}, "", err
+	}
+
+	partialThread := domain.FromModelUpdate(threadUpdate)
+	partialThread.IfMatch = ifMatch
+
+	editor, _ := identity.Caller(ctx)
+	edited, err := u.threadRepo.Patch(ctx, thread.Id, partialThread, editor)
+	if err != nil {
+		return models.Thread{}, "", err
+	}
+
+	return edited.ToModel(), edited.ETag(), nil
+}
+
+func (u *ThreadUseCaseImpl) GetRevisionsBySlugOrId(ctx context.Context, slugOrId string) (_ models.ThreadRevisions, err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.GetRevisionsBySlugOrId")
+	defer tracing.End(span, &err)
+
+	thread, err := u.getBySlugOrId(ctx, slugOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.threadRepo.GetRevisions(ctx, thread.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	obtained := make(models.ThreadRevisions, 0, len(revisions))
+	for _, revision := range revisions {
+		obtained = append(obtained, revision.ToModel())
+	}
+
+	return obtained, nil
+}
+
+func (u *ThreadUseCaseImpl) DeleteBySlugOrId(ctx context.Context, slugOrId string) (err error) {
+	ctx, span := tracing.Start(ctx, "ThreadUseCase.DeleteBySlugOrId")
+	defer tracing.End(span, &err)
+
+	thread, err := u.getBySlugOrId(ctx, slugOrId)
+	if err != nil {
+		return err
+	}
+
+	if err := identity.Manage(ctx, u.forumRepo, thread.Forum, thread.Author); err != nil {
+		return err
+	}
+
+	return u.threadRepo.Delete(ctx, thread.Id)
 }
 
-func (u *ThreadUseCaseImpl) PatchBySlugOrId(ctx context.Context, slugOrId string, threadUpdate models.ThreadUpdate) (models.Thread, error) {
+func (u *ThreadUseCaseImpl) getBySlugOrId(ctx context.Context, slugOrId string) (domain.Thread, error) {
 	id, err := strconv.ParseInt(slugOrId, 10, 64)
 
 	if err != nil {
-		edited, err := u.threadRepo.PatchBySlug(ctx, slugOrId, domain.FromModelUpdate(threadUpdate))
-		return edited.ToModel(), err
+		return u.threadRepo.GetBySlug(ctx, slugOrId)
 	} else {
-		edited, err := u.threadRepo.Patch(ctx, id, domain.FromModelUpdate(threadUpdate))
-		return edited.ToModel(), err
+		return u.threadRepo.GetById(ctx, id)
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/middlewares"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -12,10 +14,11 @@ import (
 
 type UserUseCase interface {
 	Create(ctx context.Context, user models.User) (models.Users, error)
-	Patch(ctx context.Context, nickname string, partialUser models.UserUpdate) (models.User, error)
+	Patch(ctx context.Context, nickname string, partialUser models.UserUpdate, ifMatch []byte) (models.User, string, error)
 	GetByEmail(ctx context.Context, email string) (models.User, error)
-	GetByNickname(ctx context.Context, nickname string) (models.User, error)
+	GetByNickname(ctx context.Context, nickname string) (models.User, string, error)
 	GetByEmailOrNickname(ctx context.Context, email, nickname string) (models.Users, error)
+	Delete(ctx context.Context, nickname string) error
 }
 
 type UserHandler struct {
@@ -37,7 +40,8 @@ func (h *UserHandler) Create(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse nickname: %v", rctx.UserValue("nickname"))
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid nickname",
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -52,7 +56,8 @@ func (h *UserHandler) Create(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -67,7 +72,8 @@ func (h *UserHandler) Create(rctx *fasthttp.RequestCtx) {
 
 			if err != nil {
 				body, _ = json.Marshal(models.Error{
-					Message: "internal server error",
+					Message:   "internal server error",
+					RequestId: middlewares.RequestIdFrom(ctx),
 				})
 			}
 
@@ -76,7 +82,8 @@ func (h *UserHandler) Create(rctx *fasthttp.RequestCtx) {
 			return
 		}
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -89,7 +96,8 @@ func (h *UserHandler) Create(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -110,7 +118,8 @@ func (h *UserHandler) GetProfileByNickname(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse nickname: %v", nickname)
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid nickname",
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -118,11 +127,12 @@ func (h *UserHandler) GetProfileByNickname(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	obtained, err := h.userUseCase.GetByNickname(ctx, nickname)
+	obtained, tag, err := h.userUseCase.GetByNickname(ctx, nickname)
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "user not found",
+				Message:   "user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -131,7 +141,8 @@ func (h *UserHandler) GetProfileByNickname(rctx *fasthttp.RequestCtx) {
 		}
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -144,7 +155,8 @@ func (h *UserHandler) GetProfileByNickname(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -152,6 +164,13 @@ func (h *UserHandler) GetProfileByNickname(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
+
+	if etag.Match(rctx.Request.Header.Peek(fasthttp.HeaderIfNoneMatch), tag) {
+		rctx.SetStatusCode(fasthttp.StatusNotModified)
+		return
+	}
+
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
@@ -165,7 +184,8 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 	if !ok {
 		log.Errorf("Can't parse nickname: %v", nickname)
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid nickname",
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -178,7 +198,8 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "invalid body",
+			Message:   "invalid body",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusBadRequest)
@@ -186,11 +207,12 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	edited, err := h.userUseCase.Patch(ctx, nickname, toEdit)
+	edited, tag, err := h.userUseCase.Patch(ctx, nickname, toEdit, rctx.Request.Header.Peek(fasthttp.HeaderIfMatch))
 	if err != nil {
 		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "user not found",
+				Message:   "user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -200,7 +222,8 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 
 		if _, ok := err.(forumErrors.UniqueError); ok {
 			body, _ := json.Marshal(models.Error{
-				Message: "conflict with another user's data",
+				Message:   "conflict with another user's data",
+				RequestId: middlewares.RequestIdFrom(ctx),
 			})
 
 			rctx.SetStatusCode(fasthttp.StatusConflict)
@@ -208,8 +231,43 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 			return
 		}
 
+		if _, ok := err.(forumErrors.PreconditionFailedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "user was modified since it was fetched",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusPreconditionFailed)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not edit another user's profile",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -222,7 +280,8 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 		log.Error(err.Error())
 
 		body, _ := json.Marshal(models.Error{
-			Message: "internal server error",
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
 		})
 
 		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -230,6 +289,86 @@ func (h *UserHandler) EditProfileByNickname(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	rctx.Response.Header.Set(fasthttp.HeaderETag, tag)
 	rctx.SetStatusCode(fasthttp.StatusOK)
 	rctx.SetBody(body)
 }
+
+func (h *UserHandler) Delete(rctx *fasthttp.RequestCtx) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+	rctx.SetContentType("application/json")
+
+	nickname, ok := rctx.UserValue("nickname").(string)
+	if !ok {
+		log.Errorf("Can't parse nickname: %v", rctx.UserValue("nickname"))
+		body, _ := json.Marshal(models.Error{
+			Message:   "invalid nickname",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusBadRequest)
+		rctx.SetBody(body)
+		return
+	}
+
+	err := h.userUseCase.Delete(ctx, nickname)
+	if err != nil {
+		if _, ok := err.(forumErrors.EntityNotExistsError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "user not found",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusNotFound)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ConflictError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "user still owns forums, threads or posts",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusConflict)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.UnauthorizedError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "authentication required",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			rctx.SetBody(body)
+			return
+		}
+
+		if _, ok := err.(forumErrors.ForbiddenError); ok {
+			body, _ := json.Marshal(models.Error{
+				Message:   "can not delete another user",
+				RequestId: middlewares.RequestIdFrom(ctx),
+			})
+
+			rctx.SetStatusCode(fasthttp.StatusForbidden)
+			rctx.SetBody(body)
+			return
+		}
+
+		body, _ := json.Marshal(models.Error{
+			Message:   "internal server error",
+			RequestId: middlewares.RequestIdFrom(ctx),
+		})
+
+		rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		rctx.SetBody(body)
+		return
+	}
+
+	rctx.Response.Header.Del(fasthttp.HeaderContentType)
+	rctx.SetStatusCode(fasthttp.StatusNoContent)
+}
@@ -6,6 +6,8 @@ type PartialUser struct {
 	Fullname *string
 	About    *string
 	Email    *string
+	// IfMatch is checked against the ETag of the user under its row lock.
+	IfMatch []byte
 }
 
 func GetPartial(dto models.UserUpdate) PartialUser {
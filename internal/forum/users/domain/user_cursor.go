@@ -0,0 +1,13 @@
+package domain
+
+type Cursor struct {
+	Nickname string `json:"n"`
+	Desc     bool   `json:"d,omitempty"`
+}
+
+func (user User) Cursor(desc bool) Cursor {
+	return Cursor{
+		Nickname: user.Nickname,
+		Desc:     desc,
+	}
+}
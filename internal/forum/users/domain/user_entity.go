@@ -1,6 +1,9 @@
 package domain
 
-import "github.com/rflban/parkmail-dbms/pkg/forum/models"
+import (
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+)
 
 type User struct {
 	Id       int64
@@ -19,6 +22,14 @@ func GetUserEntity(dto models.User) User {
 	}
 }
 
+func (entity User) ETag() string {
+	var about string
+	if entity.About != nil {
+		about = *entity.About
+	}
+	return etag.OfFields("user", entity.Nickname, entity.Fullname, about, entity.Email)
+}
+
 func (entity User) ToModel() models.User {
 	return models.User{
 		Nickname: &entity.Nickname,
@@ -5,38 +5,52 @@ import (
 	"errors"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/users/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/etag"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	queryCreate               = `INSERT INTO users (nickname, fullname, about, email) VALUES ($1, $2, $3, $4) RETURNING id;`
+	queryCreateWithPassword   = `WITH created AS (INSERT INTO users (nickname, fullname, about, email) VALUES ($1, $2, $3, $4) RETURNING id, nickname), credentials AS (INSERT INTO credentials (nickname, password_hash) SELECT nickname, $5 FROM created) SELECT id FROM created;`
 	queryPatch                = `UPDATE users SET fullname = COALESCE(NULLIF(TRIM($2), ''), fullname), about = COALESCE(NULLIF(TRIM($3), ''), about), email = COALESCE(NULLIF(TRIM($4), ''), email) WHERE nickname = $1 RETURNING nickname, fullname, about, email;`
 	queryGetByEmail           = `SELECT id, nickname, fullname, about, email FROM users WHERE email = $1;`
 	queryGetByNickname        = `SELECT id, nickname, fullname, about, email FROM users WHERE nickname = $1;`
+	queryGetForUpdate         = `SELECT id, nickname, fullname, about, email FROM users WHERE nickname = $1 FOR UPDATE;`
 	queryGetByEmailOrNickname = `SELECT id, nickname, fullname, about, email FROM users WHERE email = $1 OR nickname = $2;`
+	queryDelete               = `DELETE FROM users WHERE nickname = $1;`
 )
 
 type UserRepositoryPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *UserRepositoryPostgres {
+func New(db *pgxhook.Pool) *UserRepositoryPostgres {
 	return &UserRepositoryPostgres{
 		db: db,
 	}
 }
 
-func (r *UserRepositoryPostgres) Create(ctx context.Context, user domain.User) (domain.User, error) {
+// Create inserts the user and, if passwordHash is set, their credentials
+// in the same statement, so a user is never left without the password
+// they signed up with.
+func (r *UserRepositoryPostgres) Create(ctx context.Context, user domain.User, passwordHash string) (domain.User, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "User",
 		"method": "Create",
 	})
 
-	err := r.db.QueryRow(ctx, queryCreate, user.Nickname, user.Fullname, user.About, user.Email).Scan(&user.Id)
+	var row pgx.Row
+	if passwordHash == "" {
+		row = r.db.QueryRow(ctx, queryCreate, user.Nickname, user.Fullname, user.About, user.Email)
+	} else {
+		row = r.db.QueryRow(ctx, queryCreateWithPassword, user.Nickname, user.Fullname, user.About, user.Email, passwordHash)
+	}
+
+	err := row.Scan(&user.Id)
 
 	if err != nil {
 		log.Error(err.Error())
@@ -53,12 +67,19 @@ func (r *UserRepositoryPostgres) Create(ctx context.Context, user domain.User) (
 	return user, err
 }
 
+// Patch updates the user. With an IfMatch set, the user is locked and
+// checked against it in the same transaction as the update; otherwise the
+// update is a single statement.
 func (r *UserRepositoryPostgres) Patch(ctx context.Context, nickname string, partialUser domain.PartialUser) (domain.User, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "User",
 		"method": "Patch",
 	})
 
+	if len(partialUser.IfMatch) != 0 {
+		return r.patchIfMatch(ctx, log, nickname, partialUser)
+	}
+
 	var user domain.User
 
 	err := r.db.QueryRow(ctx, queryPatch, nickname, partialUser.Fullname, partialUser.About, partialUser.Email).Scan(
@@ -67,23 +88,101 @@ func (r *UserRepositoryPostgres) Patch(ctx context.Context, nickname string, par
 		&user.About,
 		&user.Email,
 	)
+
+	return user, r.patchError(log, err)
+}
+
+func (r *UserRepositoryPostgres) patchIfMatch(ctx context.Context, log *logrus.Entry, nickname string, partialUser domain.PartialUser) (domain.User, error) {
+	var (
+		user     domain.User
+		previous domain.User
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return user, err
+	}
+
+	err = tx.QueryRow(ctx, queryGetForUpdate, nickname).Scan(
+		&previous.Id,
+		&previous.Nickname,
+		&previous.Fullname,
+		&previous.About,
+		&previous.Email,
+	)
+	if err == nil && !etag.MatchStrong(partialUser.IfMatch, previous.ETag()) {
+		err = forumErrors.NewPreconditionFailedError("user")
+	}
+	if err == nil {
+		err = tx.QueryRow(ctx, queryPatch, nickname, partialUser.Fullname, partialUser.About, partialUser.Email).Scan(
+			&user.Nickname,
+			&user.Fullname,
+			&user.About,
+			&user.Email,
+		)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(ctx); err != nil {
+			log.Error(err.Error())
+		}
+		return user, r.patchError(log, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	return user, err
+}
+
+func (r *UserRepositoryPostgres) patchError(log *logrus.Entry, err error) error {
 	if err != nil {
 		log.Error(err.Error())
 
 		if err.Error() == pgx.ErrNoRows.Error() {
-			return user, forumErrors.NewEntityNotExistsError("users")
+			return forumErrors.NewEntityNotExistsError("users")
 		}
 
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.SQLState() == "23505" {
-			return user, forumErrors.NewUniqueError(
+			return forumErrors.NewUniqueError(
 				pgErr.TableName,
 				pgErr.ColumnName,
 			)
 		}
 	}
 
-	return user, err
+	return err
+}
+
+// Delete removes the user along with their votes, credentials and forum
+// memberships. Users who still own forums, threads or posts are kept, as
+// removing them would take the content of other users along.
+func (r *UserRepositoryPostgres) Delete(ctx context.Context, nickname string) error {
+	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"repo":   "User",
+		"method": "Delete",
+	})
+
+	tag, err := r.db.Exec(ctx, queryDelete, nickname)
+	if err != nil {
+		log.Error(err.Error())
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.SQLState() == "23503" {
+			return forumErrors.NewConflictError("user still owns forums, threads or posts")
+		}
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return forumErrors.NewEntityNotExistsError("users")
+	}
+
+	return nil
 }
 
 func (r *UserRepositoryPostgres) GetByEmail(ctx context.Context, email string) (domain.User, error) {
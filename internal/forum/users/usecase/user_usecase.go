@@ -2,17 +2,21 @@ package usecase
 
 import (
 	"context"
+	authDomain "github.com/rflban/parkmail-dbms/internal/forum/auth/domain"
 	"github.com/rflban/parkmail-dbms/internal/forum/users/domain"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, user domain.User) (domain.User, error)
+	Create(ctx context.Context, user domain.User, passwordHash string) (domain.User, error)
 	Patch(ctx context.Context, nickname string, partialUser domain.PartialUser) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	GetByNickname(ctx context.Context, nickname string) (domain.User, error)
 	GetByEmailOrNickname(ctx context.Context, email, nickname string) ([]domain.User, error)
+	Delete(ctx context.Context, nickname string) error
 }
 
 type UserUseCaseImpl struct {
@@ -25,8 +29,20 @@ func New(userRepo UserRepository) *UserUseCaseImpl {
 	}
 }
 
-func (u *UserUseCaseImpl) Create(ctx context.Context, user models.User) (models.Users, error) {
-	created, err := u.userRepo.Create(ctx, domain.GetUserEntity(user))
+func (u *UserUseCaseImpl) Create(ctx context.Context, user models.User) (_ models.Users, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.Create")
+	defer tracing.End(span, &err)
+
+	var passwordHash string
+	if user.Password != "" {
+		hash, err := authDomain.HashPassword(user.Password)
+		if err != nil {
+			return nil, err
+		}
+		passwordHash = hash
+	}
+
+	created, err := u.userRepo.Create(ctx, domain.GetUserEntity(user), passwordHash)
 
 	if err == nil {
 		return models.Users{created.ToModel()}, nil
@@ -55,22 +71,63 @@ func (u *UserUseCaseImpl) Create(ctx context.Context, user models.User) (models.
 	return users, conflict
 }
 
-func (u *UserUseCaseImpl) Patch(ctx context.Context, nickname string, partialUser models.UserUpdate) (models.User, error) {
-	updated, err := u.userRepo.Patch(ctx, nickname, domain.GetPartial(partialUser))
-	return updated.ToModel(), err
+// Patch edits the profile and returns it with its new ETag. A non-empty
+// ifMatch makes the edit conditional on the current ETag.
+func (u *UserUseCaseImpl) Patch(ctx context.Context, nickname string, partialUser models.UserUpdate, ifMatch []byte) (_ models.User, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.Patch")
+	defer tracing.End(span, &err)
+
+	if err := identity.Own(ctx, nickname); err != nil {
+		return models.User{}, "", err
+	}
+
+	partial := domain.GetPartial(partialUser)
+	partial.IfMatch = ifMatch
+
+	updated, err := u.userRepo.Patch(ctx, nickname, partial)
+	if err != nil {
+		return models.User{}, "", err
+	}
+
+	return updated.ToModel(), updated.ETag(), nil
 }
 
-func (u *UserUseCaseImpl) GetByEmail(ctx context.Context, email string) (models.User, error) {
+func (u *UserUseCaseImpl) Delete(ctx context.Context, nickname string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.Delete")
+	defer tracing.End(span, &err)
+
+	if err := identity.Own(ctx, nickname); err != nil {
+		return err
+	}
+
+	return u.userRepo.Delete(ctx, nickname)
+}
+
+func (u *UserUseCaseImpl) GetByEmail(ctx context.Context, email string) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.GetByEmail")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.userRepo.GetByEmail(ctx, email)
 	return obtained.ToModel(), err
 }
 
-func (u *UserUseCaseImpl) GetByNickname(ctx context.Context, nickname string) (models.User, error) {
+// GetByNickname returns the user along with their ETag.
+func (u *UserUseCaseImpl) GetByNickname(ctx context.Context, nickname string) (_ models.User, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.GetByNickname")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.userRepo.GetByNickname(ctx, nickname)
-	return obtained.ToModel(), err
+	if err != nil {
+		return models.User{}, "", err
+	}
+
+	return obtained.ToModel(), obtained.ETag(), nil
 }
 
-func (u *UserUseCaseImpl) GetByEmailOrNickname(ctx context.Context, email, nickname string) (models.Users, error) {
+func (u *UserUseCaseImpl) GetByEmailOrNickname(ctx context.Context, email, nickname string) (_ models.Users, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.GetByEmailOrNickname")
+	defer tracing.End(span, &err)
+
 	obtained, err := u.userRepo.GetByEmailOrNickname(ctx, email, nickname)
 
 	users := make([]models.User, 0, len(obtained))
@@ -5,10 +5,10 @@ import (
 	"errors"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rflban/parkmail-dbms/internal/forum/votes/domain"
 	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
 	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,14 +18,16 @@ const (
 	querySetByThreadId = `
 							INSERT INTO votes (nickname, thread, voice) VALUES ($1, $2, $3)
 					 		ON CONFLICT (nickname, thread) DO UPDATE
-								SET voice = $3;`
+								SET voice = $3
+							RETURNING xmax = 0;`
 	querySetByThreadSlug = `
 							INSERT INTO votes (nickname, thread, voice) 
 								SELECT $1, id, $3
 								FROM threads
 								WHERE slug = $2
 					 		ON CONFLICT (nickname, thread) DO UPDATE
-								SET voice = $3;`
+								SET voice = $3
+							RETURNING xmax = 0;`
 	queryPatch = `
 					UPDATE votes
 					SET voice = COALESCE(NULLIF(TRIM($3), ''), voice)
@@ -34,22 +36,24 @@ const (
 )
 
 type VoteRepositoryPostgres struct {
-	db *pgxpool.Pool
+	db *pgxhook.Pool
 }
 
-func New(db *pgxpool.Pool) *VoteRepositoryPostgres {
+func New(db *pgxhook.Pool) *VoteRepositoryPostgres {
 	return &VoteRepositoryPostgres{
 		db: db,
 	}
 }
 
-func (r *VoteRepositoryPostgres) Set(ctx context.Context, vote domain.Vote) (domain.Vote, error) {
+// Set inserts or updates the vote and reports whether it was inserted.
+func (r *VoteRepositoryPostgres) Set(ctx context.Context, vote domain.Vote) (domain.Vote, bool, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Vote",
 		"method": "Set",
 	})
 
-	_, err := r.db.Exec(ctx, querySetByThreadId, vote.Nickname, vote.Thread, vote.Voice)
+	var inserted bool
+	err := r.db.QueryRow(ctx, querySetByThreadId, vote.Nickname, vote.Thread, vote.Voice).Scan(&inserted)
 	if err != nil {
 		log.Error(err.Error())
 
@@ -57,21 +61,27 @@ func (r *VoteRepositoryPostgres) Set(ctx context.Context, vote domain.Vote) (dom
 		if errors.As(err, &pgErr) {
 			switch pgErr.SQLState() {
 			case "23503":
-				return vote, forumErrors.NewEntityNotExistsError("votes")
+				return vote, false, forumErrors.NewEntityNotExistsError("votes")
 			}
 		}
 	}
 
-	return vote, err
+	return vote, inserted, err
 }
 
-func (r *VoteRepositoryPostgres) SetByThreadSlug(ctx context.Context, slug string, vote domain.Vote) (domain.Vote, error) {
+// SetByThreadSlug inserts or updates the vote and reports whether it was
+// inserted. Nothing is set when no thread has the slug.
+func (r *VoteRepositoryPostgres) SetByThreadSlug(ctx context.Context, slug string, vote domain.Vote) (domain.Vote, bool, error) {
 	log := ctx.Value(constants.RepoLogKey).(*logrus.Entry).WithFields(logrus.Fields{
 		"repo":   "Vote",
 		"method": "SetByThreadSlug",
 	})
 
-	_, err := r.db.Exec(ctx, querySetByThreadSlug, vote.Nickname, slug, vote.Voice)
+	var inserted bool
+	err := r.db.QueryRow(ctx, querySetByThreadSlug, vote.Nickname, slug, vote.Voice).Scan(&inserted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return vote, false, nil
+	}
 	if err != nil {
 		log.Error(err.Error())
 
@@ -79,12 +89,12 @@ func (r *VoteRepositoryPostgres) SetByThreadSlug(ctx context.Context, slug strin
 		if errors.As(err, &pgErr) {
 			switch pgErr.SQLState() {
 			case "23503":
-				return vote, forumErrors.NewEntityNotExistsError("votes")
+				return vote, false, forumErrors.NewEntityNotExistsError("votes")
 			}
 		}
 	}
 
-	return vote, err
+	return vote, inserted, err
 }
 
 func (r *VoteRepositoryPostgres) Create(ctx context.Context, vote domain.Vote) (domain.Vote, error) {
@@ -4,13 +4,16 @@ import (
 	"context"
 	threadsDomain "github.com/rflban/parkmail-dbms/internal/forum/threads/domain"
 	"github.com/rflban/parkmail-dbms/internal/forum/votes/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
 	"github.com/rflban/parkmail-dbms/pkg/forum/models"
 	"strconv"
 )
 
 type VoteRepository interface {
-	Set(ctx context.Context, vote domain.Vote) (domain.Vote, error)
-	SetByThreadSlug(ctx context.Context, slug string, vote domain.Vote) (domain.Vote, error)
+	Set(ctx context.Context, vote domain.Vote) (domain.Vote, bool, error)
+	SetByThreadSlug(ctx context.Context, slug string, vote domain.Vote) (domain.Vote, bool, error)
 	Create(ctx context.Context, vote domain.Vote) (domain.Vote, error)
 	Exists(ctx context.Context, nickname string, thread int64) (bool, error)
 	Patch(ctx context.Context, nickname string, thread int64, voice *int64) (domain.Vote, error)
@@ -33,21 +36,33 @@ func New(voteRepo VoteRepository, threadRepo ThreadRepository) *VoteUseCaseImpl
 	}
 }
 
-func (u *VoteUseCaseImpl) Set(ctx context.Context, thread string, vote models.Vote) (models.Thread, error) {
+func (u *VoteUseCaseImpl) Set(ctx context.Context, thread string, vote models.Vote) (_ models.Thread, err error) {
+	ctx, span := tracing.Start(ctx, "VoteUseCase.Set")
+	defer tracing.End(span, &err)
+
+	if err := identity.ActAs(ctx, vote.Nickname); err != nil {
+		return models.Thread{}, err
+	}
+
 	threadId, err := strconv.ParseInt(thread, 10, 64)
 	toSet := domain.FromModel(vote, threadId)
 
 	var (
 		threadEntity threadsDomain.Thread
+		inserted     bool
 	)
 
 	isThreadSlug := err != nil
 
 	if isThreadSlug {
-		_, err = u.voteRepo.SetByThreadSlug(ctx, thread, toSet)
+		_, inserted, err = u.voteRepo.SetByThreadSlug(ctx, thread, toSet)
 	} else {
 		toSet.Thread = threadId
-		_, err = u.voteRepo.Set(ctx, toSet)
+		_, inserted, err = u.voteRepo.Set(ctx, toSet)
+	}
+
+	if err == nil && inserted {
+		metrics.VoteCreated()
 	}
 
 	if err == nil {
@@ -62,17 +77,29 @@ func (u *VoteUseCaseImpl) Set(ctx context.Context, thread string, vote models.Vo
 	return threadEntity.ToModel(), err
 }
 
-func (u *VoteUseCaseImpl) Create(ctx context.Context, thread int64, vote models.Vote) (models.Vote, error) {
+func (u *VoteUseCaseImpl) Create(ctx context.Context, thread int64, vote models.Vote) (_ models.Vote, err error) {
+	ctx, span := tracing.Start(ctx, "VoteUseCase.Create")
+	defer tracing.End(span, &err)
+
 	created, err := u.voteRepo.Create(ctx, domain.FromModel(vote, thread))
+	if err == nil {
+		metrics.VoteCreated()
+	}
 	return created.ToModel(), err
 }
 
-func (u *VoteUseCaseImpl) Exists(ctx context.Context, nickname string, thread int64) (bool, error) {
+func (u *VoteUseCaseImpl) Exists(ctx context.Context, nickname string, thread int64) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "VoteUseCase.Exists")
+	defer tracing.End(span, &err)
+
 	exists, err := u.voteRepo.Exists(ctx, nickname, thread)
 	return exists, err
 }
 
-func (u *VoteUseCaseImpl) Patch(ctx context.Context, nickname string, thread int64, voice *int64) (models.Vote, error) {
+func (u *VoteUseCaseImpl) Patch(ctx context.Context, nickname string, thread int64, voice *int64) (_ models.Vote, err error) {
+	ctx, span := tracing.Start(ctx, "VoteUseCase.Patch")
+	defer tracing.End(span, &err)
+
 	edited, err := u.voteRepo.Patch(ctx, nickname, thread, voice)
 	return edited.ToModel(), err
 }
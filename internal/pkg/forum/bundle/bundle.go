@@ -0,0 +1,64 @@
+// Package bundle defines the stream a whole forum is exported to and read
+// back from: records of several kinds, in the order their references
+// require, either as NDJSON or as a tar of an NDJSON file per kind.
+package bundle
+
+type Kind string
+
+const (
+	Users   Kind = "users"
+	Forums  Kind = "forums"
+	Threads Kind = "threads"
+	Posts   Kind = "posts"
+	Votes   Kind = "votes"
+)
+
+// Kinds lists every kind in the order their references require.
+var Kinds = []Kind{Users, Forums, Threads, Posts, Votes}
+
+func IsValidKind(kind Kind) bool {
+	for _, known := range Kinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	FormatNDJSON = "ndjson"
+	FormatTar    = "tar"
+)
+
+// ndjsonExtension ends the names of the files of a tar bundle.
+const ndjsonExtension = ".ndjson"
+
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatNDJSON, FormatTar:
+		return true
+	default:
+		return false
+	}
+}
+
+func ContentType(format string) string {
+	if format == FormatTar {
+		return "application/x-tar"
+	}
+	return "application/x-ndjson"
+}
+
+// Vote is a vote together with the thread it is cast in, which
+// models.Vote leaves to the URL.
+type Vote struct {
+	Nickname string `json:"nickname"`
+	Voice    int32  `json:"voice"`
+	Thread   int64  `json:"thread"`
+}
+
+// record is a line of an NDJSON bundle.
+type record struct {
+	Kind Kind        `json:"kind"`
+	Data interface{} `json:"data"`
+}
@@ -0,0 +1,106 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Reader yields the records of a bundle one by one and returns io.EOF
+// after the last one.
+type Reader interface {
+	Next() (Kind, []byte, error)
+}
+
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonReader{in: bufio.NewReaderSize(r, 1<<20)}, nil
+	case FormatTar:
+		return &tarReader{in: tar.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown bundle format: %s", format)
+	}
+}
+
+type ndjsonReader struct {
+	in   *bufio.Reader
+	kind Kind
+}
+
+// Next passes a line that is not a valid record on under the kind of the
+// previous one, so it is rejected together with the rows it belongs to.
+func (r *ndjsonReader) Next() (Kind, []byte, error) {
+	for {
+		raw, err := r.in.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(raw) == 0) {
+			return "", nil, err
+		}
+
+		line := bytes.TrimSpace(raw)
+		if len(line) == 0 {
+			continue
+		}
+
+		var parsed struct {
+			Kind Kind            `json:"kind"`
+			Data json.RawMessage `json:"data"`
+		}
+		if json.Unmarshal(line, &parsed) == nil && IsValidKind(parsed.Kind) {
+			r.kind = parsed.Kind
+			return parsed.Kind, parsed.Data, nil
+		}
+
+		if r.kind == "" {
+			return "", nil, fmt.Errorf("invalid bundle record: %.64s", line)
+		}
+		return r.kind, line, nil
+	}
+}
+
+type tarReader struct {
+	in   *tar.Reader
+	file *bufio.Reader
+	kind Kind
+}
+
+// Next reads the records of a file line by line and skips entries that are
+// not files of a known kind.
+func (r *tarReader) Next() (Kind, []byte, error) {
+	for {
+		if r.file != nil {
+			raw, err := r.file.ReadBytes('\n')
+			if err != nil && (err != io.EOF || len(raw) == 0) {
+				if err != io.EOF {
+					return "", nil, err
+				}
+				r.file = nil
+				continue
+			}
+
+			line := bytes.TrimSpace(raw)
+			if len(line) == 0 {
+				continue
+			}
+			return r.kind, line, nil
+		}
+
+		header, err := r.in.Next()
+		if err != nil {
+			return "", nil, err
+		}
+
+		kind := Kind(strings.TrimSuffix(path.Base(path.Clean(header.Name)), ndjsonExtension))
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ndjsonExtension) || !IsValidKind(kind) {
+			continue
+		}
+
+		r.kind = kind
+		r.file = bufio.NewReaderSize(r.in, 1<<20)
+	}
+}
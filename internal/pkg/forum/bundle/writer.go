@@ -0,0 +1,142 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type Writer interface {
+	Write(kind Kind, item interface{}) error
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{out: bufio.NewWriter(w)}, nil
+	case FormatTar:
+		return &tarWriter{out: tar.NewWriter(w), modTime: time.Now()}, nil
+	default:
+		return nil, fmt.Errorf("unknown bundle format: %s", format)
+	}
+}
+
+type ndjsonWriter struct {
+	out *bufio.Writer
+}
+
+func (w *ndjsonWriter) Write(kind Kind, item interface{}) error {
+	line, err := json.Marshal(record{Kind: kind, Data: item})
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.out.Write(line); err != nil {
+		return err
+	}
+	return w.out.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.out.Flush()
+}
+
+// tarWriter stores the records of every kind as one NDJSON file named
+// after the kind. A tar header needs the size of its file up front, so the
+// records of the current kind are spooled to a temporary file first.
+type tarWriter struct {
+	out     *tar.Writer
+	modTime time.Time
+	kind    Kind
+	spool   *os.File
+	spooled *bufio.Writer
+	size    int64
+}
+
+func (w *tarWriter) Write(kind Kind, item interface{}) error {
+	line, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if kind != w.kind || w.spool == nil {
+		if err = w.flush(); err != nil {
+			return err
+		}
+		if err = w.startSpool(kind); err != nil {
+			return err
+		}
+	}
+
+	if _, err = w.spooled.Write(line); err != nil {
+		return err
+	}
+	if err = w.spooled.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.size += int64(len(line)) + 1
+
+	return nil
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return w.out.Close()
+}
+
+// startSpool opens the temporary file for kind. The file is unlinked right
+// away, so nothing is left behind if the export is cut short.
+func (w *tarWriter) startSpool(kind Kind) error {
+	spool, err := os.CreateTemp("", "forum-export-*.ndjson")
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(spool.Name()); err != nil {
+		spool.Close()
+		return err
+	}
+
+	w.kind, w.spool, w.size = kind, spool, 0
+	w.spooled = bufio.NewWriterSize(spool, 1<<20)
+
+	return nil
+}
+
+// flush writes the spooled records out as the file of their kind.
+func (w *tarWriter) flush() error {
+	if w.spool == nil {
+		return nil
+	}
+
+	spool := w.spool
+	w.spool = nil
+	defer spool.Close()
+
+	if err := w.spooled.Flush(); err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	err := w.out.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     string(w.kind) + ndjsonExtension,
+		Size:     w.size,
+		Mode:     0o644,
+		ModTime:  w.modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(w.out, spool, w.size)
+	return err
+}
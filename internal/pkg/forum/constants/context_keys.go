@@ -1,9 +1,15 @@
 package constants
 
 const (
-	SetupLogKey    = "setup_log"
-	AccessLogKey   = "access_log"
-	DeliveryLogKey = "delivery_log"
-	UseCaseLogKey  = "usecase_log"
-	RepoLogKey     = "repo_log"
+	SetupLogKey     = "setup_log"
+	AccessLogKey    = "access_log"
+	DeliveryLogKey  = "delivery_log"
+	UseCaseLogKey   = "usecase_log"
+	RepoLogKey      = "repo_log"
+	SlowQueryLogKey = "slow_query_log"
+
+	PrivilegedKey = "privileged"
+	CallerKey     = "caller"
+	AdminKey      = "admin"
+	RequestIdKey  = "request_id"
 )
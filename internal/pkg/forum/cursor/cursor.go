@@ -0,0 +1,39 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// NextHeader carries the token of the following page on list responses,
+// which keep their plain array bodies.
+const NextHeader = "X-Next-Cursor"
+
+func Encode(position interface{}) (string, error) {
+	raw, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func Decode(token string, position interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, position)
+}
+
+// FetchLimit asks for one row past the page so that HasMore can tell whether
+// a next page exists without a separate count. Zero means no limit.
+func FetchLimit(limit uint64) uint64 {
+	if limit == 0 {
+		return 0
+	}
+	return limit + 1
+}
+
+func HasMore(obtained int, limit uint64) bool {
+	return limit > 0 && uint64(obtained) > limit
+}
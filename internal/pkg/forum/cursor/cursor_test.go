@@ -0,0 +1,92 @@
+package cursor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type position struct {
+	Sort string  `json:"s"`
+	Desc bool    `json:"d"`
+	Id   int64   `json:"i"`
+	Path []int64 `json:"p,omitempty"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		position position
+	}{
+		{name: "zero", position: position{}},
+		{name: "flat", position: position{Sort: "flat", Id: 42}},
+		{name: "descending tree", position: position{Sort: "tree", Desc: true, Id: 7, Path: []int64{1, 3, 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := Encode(tt.position)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if strings.ContainsAny(token, "+/=") {
+				t.Errorf("token %q is not URL safe", token)
+			}
+
+			var decoded position
+			if err = Decode(token, &decoded); err != nil {
+				t.Fatalf("Decode(%q) error = %v", token, err)
+			}
+			if !reflect.DeepEqual(decoded, tt.position) {
+				t.Errorf("Decode(Encode(%+v)) = %+v", tt.position, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsInvalidTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "not a cursor!"},
+		{name: "padded", token: "e30="},
+		{name: "not json", token: "bm90IGpzb24"},
+		{name: "wrong type", token: "eyJpIjoiYSJ9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded position
+			if err := Decode(tt.token, &decoded); err == nil {
+				t.Errorf("Decode(%q) = %+v, want an error", tt.token, decoded)
+			}
+		})
+	}
+}
+
+func TestFetchLimitAndHasMore(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    uint64
+		obtained int
+		fetch    uint64
+		hasMore  bool
+	}{
+		{name: "no limit", limit: 0, obtained: 100, fetch: 0, hasMore: false},
+		{name: "short page", limit: 10, obtained: 3, fetch: 11, hasMore: false},
+		{name: "full page", limit: 10, obtained: 10, fetch: 11, hasMore: false},
+		{name: "page and one more", limit: 10, obtained: 11, fetch: 11, hasMore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FetchLimit(tt.limit); got != tt.fetch {
+				t.Errorf("FetchLimit(%d) = %d, want %d", tt.limit, got, tt.fetch)
+			}
+			if got := HasMore(tt.obtained, tt.limit); got != tt.hasMore {
+				t.Errorf("HasMore(%d, %d) = %v, want %v", tt.obtained, tt.limit, got, tt.hasMore)
+			}
+		})
+	}
+}
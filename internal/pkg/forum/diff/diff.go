@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+
+	maxTableSize = 4_000_000
+)
+
+var wordPattern = regexp.MustCompile(`\s+|\S+`)
+
+type Chunk struct {
+	Op   string
+	Text string
+}
+
+func Lines(from, to string) []Chunk {
+	return compute(splitLines(from), splitLines(to))
+}
+
+func Words(from, to string) []Chunk {
+	return compute(wordPattern.FindAllString(from, -1), wordPattern.FindAllString(to, -1))
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func compute(from, to []string) []Chunk {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix &&
+		from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	chunks := make([]Chunk, 0)
+	chunks = appendTokens(chunks, OpEqual, from[:prefix])
+	chunks = appendMiddle(chunks, from[prefix:len(from)-suffix], to[prefix:len(to)-suffix])
+	chunks = appendTokens(chunks, OpEqual, from[len(from)-suffix:])
+
+	return chunks
+}
+
+func appendMiddle(chunks []Chunk, from, to []string) []Chunk {
+	n, m := len(from), len(to)
+
+	if n == 0 || m == 0 || (n+1)*(m+1) > maxTableSize {
+		chunks = appendTokens(chunks, OpDelete, from)
+		return appendTokens(chunks, OpInsert, to)
+	}
+
+	width := m + 1
+	lcs := make([]int32, (n+1)*width)
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else if lcs[(i+1)*width+j] >= lcs[i*width+j+1] {
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			} else {
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case from[i] == to[j]:
+			chunks = appendToken(chunks, OpEqual, from[i])
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			chunks = appendToken(chunks, OpDelete, from[i])
+			i++
+		default:
+			chunks = appendToken(chunks, OpInsert, to[j])
+			j++
+		}
+	}
+
+	chunks = appendTokens(chunks, OpDelete, from[i:])
+	return appendTokens(chunks, OpInsert, to[j:])
+}
+
+func appendTokens(chunks []Chunk, op string, tokens []string) []Chunk {
+	if len(tokens) == 0 {
+		return chunks
+	}
+	return appendToken(chunks, op, strings.Join(tokens, ""))
+}
+
+func appendToken(chunks []Chunk, op string, token string) []Chunk {
+	if last := len(chunks) - 1; last >= 0 && chunks[last].Op == op {
+		chunks[last].Text += token
+		return chunks
+	}
+	return append(chunks, Chunk{
+		Op:   op,
+		Text: token,
+	})
+}
@@ -0,0 +1,175 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Chunk
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: []Chunk{{Op: OpEqual, Text: "a\nb\n"}},
+		},
+		{
+			name: "both empty",
+			want: []Chunk{},
+		},
+		{
+			name: "from empty",
+			to:   "a\n",
+			want: []Chunk{{Op: OpInsert, Text: "a\n"}},
+		},
+		{
+			name: "to empty",
+			from: "a\n",
+			want: []Chunk{{Op: OpDelete, Text: "a\n"}},
+		},
+		{
+			name: "line changed in the middle",
+			from: "a\nb\nc\n",
+			to:   "a\nx\nc\n",
+			want: []Chunk{
+				{Op: OpEqual, Text: "a\n"},
+				{Op: OpDelete, Text: "b\n"},
+				{Op: OpInsert, Text: "x\n"},
+				{Op: OpEqual, Text: "c\n"},
+			},
+		},
+		{
+			name: "common lines kept between changes",
+			from: "a\nb\nc\nd\n",
+			to:   "b\nc\ne\n",
+			want: []Chunk{
+				{Op: OpDelete, Text: "a\n"},
+				{Op: OpEqual, Text: "b\nc\n"},
+				{Op: OpDelete, Text: "d\n"},
+				{Op: OpInsert, Text: "e\n"},
+			},
+		},
+		{
+			name: "last line without newline",
+			from: "a\nb",
+			to:   "a\nb\n",
+			want: []Chunk{
+				{Op: OpEqual, Text: "a\n"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpInsert, Text: "b\n"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Chunk
+	}{
+		{
+			name: "word replaced",
+			from: "the quick fox",
+			to:   "the slow fox",
+			want: []Chunk{
+				{Op: OpEqual, Text: "the "},
+				{Op: OpDelete, Text: "quick"},
+				{Op: OpInsert, Text: "slow"},
+				{Op: OpEqual, Text: " fox"},
+			},
+		},
+		{
+			name: "word inserted",
+			from: "a c",
+			to:   "a b c",
+			want: []Chunk{
+				{Op: OpEqual, Text: "a "},
+				{Op: OpInsert, Text: "b "},
+				{Op: OpEqual, Text: "c"},
+			},
+		},
+		{
+			name: "whitespace changed",
+			from: "a b",
+			to:   "a  b",
+			want: []Chunk{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: " "},
+				{Op: OpInsert, Text: "  "},
+				{Op: OpEqual, Text: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Words(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestComputeKeepsLongestCommonSubsequence checks that both sides can be
+// rebuilt from the chunks and that as many tokens as possible stay equal.
+func TestComputeKeepsLongestCommonSubsequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to []string
+		common   int
+	}{
+		{name: "interleaved", from: []string{"a", "b", "c", "a", "b"}, to: []string{"b", "a", "c", "b", "a"}, common: 3},
+		{name: "disjoint", from: []string{"a", "b"}, to: []string{"c", "d", "e"}, common: 0},
+		{name: "repeated", from: []string{"x", "x", "x"}, to: []string{"x", "y", "x"}, common: 2},
+		{name: "shifted", from: []string{"a", "b", "c", "d"}, to: []string{"x", "a", "b", "c"}, common: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var from, to string
+			common := 0
+			for _, chunk := range compute(tt.from, tt.to) {
+				if chunk.Op == OpEqual {
+					common += len(chunk.Text)
+				}
+				if chunk.Op != OpInsert {
+					from += chunk.Text
+				}
+				if chunk.Op != OpDelete {
+					to += chunk.Text
+				}
+			}
+
+			if want := join(tt.from); from != want {
+				t.Errorf("old side = %q, want %q", from, want)
+			}
+			if want := join(tt.to); to != want {
+				t.Errorf("new side = %q, want %q", to, want)
+			}
+			if common != tt.common {
+				t.Errorf("%d tokens kept equal, want %d", common, tt.common)
+			}
+		})
+	}
+}
+
+func join(tokens []string) string {
+	joined := ""
+	for _, token := range tokens {
+		joined += token
+	}
+	return joined
+}
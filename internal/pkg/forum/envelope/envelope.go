@@ -0,0 +1,19 @@
+package envelope
+
+import (
+	"bytes"
+	"github.com/valyala/fasthttp"
+)
+
+var profile = []byte(`profile="envelope"`)
+
+// Requested reports whether a list should be wrapped with its pagination
+// metadata, either through ?envelope=true or an Accept profile such as
+// application/json; profile="envelope".
+func Requested(rctx *fasthttp.RequestCtx) bool {
+	if string(rctx.QueryArgs().Peek("envelope")) == "true" {
+		return true
+	}
+
+	return bytes.Contains(rctx.Request.Header.Peek("Accept"), profile)
+}
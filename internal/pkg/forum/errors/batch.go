@@ -0,0 +1,55 @@
+package errors
+
+import "fmt"
+
+const (
+	ItemAuthorUnknown     = "author_unknown"
+	ItemParentNotInThread = "parent_not_in_thread"
+)
+
+// ItemError tells why the entity at Index of a batch could not be created.
+type ItemError struct {
+	Index   int
+	Code    string
+	Message string
+}
+
+func NewAuthorUnknownError(index int, author string) ItemError {
+	return ItemError{
+		Index:   index,
+		Code:    ItemAuthorUnknown,
+		Message: fmt.Sprintf("author %s unknown", author),
+	}
+}
+
+func NewParentNotInThreadError(index int, parent int64) ItemError {
+	return ItemError{
+		Index:   index,
+		Code:    ItemParentNotInThread,
+		Message: fmt.Sprintf("parent %d not in thread", parent),
+	}
+}
+
+// BatchError rejects a whole batch because some of its items are invalid.
+type BatchError struct {
+	Items []ItemError
+}
+
+func NewBatchError(items []ItemError) BatchError {
+	return BatchError{
+		Items: items,
+	}
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("%d items of the batch are invalid", len(e.Items))
+}
+
+func (e BatchError) Has(code string) bool {
+	for _, item := range e.Items {
+		if item.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package errors
+
+import "fmt"
+
+type ForbiddenError struct {
+	action string
+}
+
+func NewForbiddenError(action string) ForbiddenError {
+	return ForbiddenError{
+		action: action,
+	}
+}
+
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("Not allowed to %s", e.action)
+}
+
+type InvalidArgumentError struct {
+	argument string
+	value    string
+}
+
+func NewInvalidArgumentError(argument, value string) InvalidArgumentError {
+	return InvalidArgumentError{
+		argument: argument,
+		value:    value,
+	}
+}
+
+func (e InvalidArgumentError) Error() string {
+	return fmt.Sprintf("Invalid value '%s' for argument '%s'", e.value, e.argument)
+}
+
+type UnauthorizedError struct {
+	reason string
+}
+
+func NewUnauthorizedError(reason string) UnauthorizedError {
+	return UnauthorizedError{
+		reason: reason,
+	}
+}
+
+func (e UnauthorizedError) Error() string {
+	return fmt.Sprintf("Unauthorized: %s", e.reason)
+}
+
+type UnavailableError struct {
+	reason string
+}
+
+func NewUnavailableError(reason string) UnavailableError {
+	return UnavailableError{
+		reason: reason,
+	}
+}
+
+func (e UnavailableError) Error() string {
+	return fmt.Sprintf("Service unavailable: %s", e.reason)
+}
+
+type PreconditionFailedError struct {
+	entity string
+}
+
+func NewPreconditionFailedError(entity string) PreconditionFailedError {
+	return PreconditionFailedError{
+		entity: entity,
+	}
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("Precondition failed: %s was modified", e.entity)
+}
@@ -0,0 +1,58 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	weakPrefix = "W/"
+	wildcard   = "*"
+)
+
+// OfFields tags a resource by the state a client can change rather than by
+// one of its renderings, so that the tag read with any representation
+// still matches when it comes back in If-Match. Fields are length prefixed
+// so that no two lists of fields share a tag.
+func OfFields(fields ...string) string {
+	hash := sha1.New()
+	for _, field := range fields {
+		hash.Write([]byte(strconv.Itoa(len(field))))
+		hash.Write([]byte{':'})
+		hash.Write([]byte(field))
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+func Match(header []byte, tag string) bool {
+	return match(header, tag, false)
+}
+
+func MatchStrong(header []byte, tag string) bool {
+	return match(header, tag, true)
+}
+
+func match(header []byte, tag string, strong bool) bool {
+	for _, candidate := range bytes.Split(header, []byte(",")) {
+		candidate = bytes.TrimSpace(candidate)
+
+		if string(candidate) == wildcard {
+			return true
+		}
+
+		if bytes.HasPrefix(candidate, []byte(weakPrefix)) {
+			if strong {
+				continue
+			}
+			candidate = candidate[len(weakPrefix):]
+		}
+
+		if string(candidate) == tag {
+			return true
+		}
+	}
+
+	return false
+}
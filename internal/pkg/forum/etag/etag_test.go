@@ -0,0 +1,65 @@
+package etag
+
+import "testing"
+
+func TestOfFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []string
+		equal bool
+	}{
+		{name: "same fields", a: []string{"post", "1", "hi"}, b: []string{"post", "1", "hi"}, equal: true},
+		{name: "field changed", a: []string{"post", "1", "hi"}, b: []string{"post", "1", "hello"}, equal: false},
+		{name: "kind changed", a: []string{"post", "1"}, b: []string{"thread", "1"}, equal: false},
+		{name: "boundary moved", a: []string{"ab", "c"}, b: []string{"a", "bc"}, equal: false},
+		{name: "fields joined", a: []string{"a", "b"}, b: []string{"ab"}, equal: false},
+		{name: "empty field", a: []string{"a", ""}, b: []string{"a"}, equal: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := OfFields(tt.a...), OfFields(tt.b...)
+			if (a == b) != tt.equal {
+				t.Errorf("OfFields(%q) = %s, OfFields(%q) = %s, want equal: %v", tt.a, a, tt.b, b, tt.equal)
+			}
+		})
+	}
+}
+
+func TestOfFieldsIsQuoted(t *testing.T) {
+	tag := OfFields("post", "1")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		t.Errorf("OfFields() = %s, want a quoted strong tag", tag)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tag := OfFields("post", "1")
+
+	tests := []struct {
+		name   string
+		header string
+		match  bool
+		strong bool
+	}{
+		{name: "same tag", header: tag, match: true, strong: true},
+		{name: "other tag", header: `"other"`, match: false, strong: false},
+		{name: "wildcard", header: "*", match: true, strong: true},
+		{name: "weak tag", header: "W/" + tag, match: true, strong: false},
+		{name: "in a list", header: `"other", ` + tag, match: true, strong: true},
+		{name: "weak in a list", header: `"other",W/` + tag, match: true, strong: false},
+		{name: "unquoted", header: tag[1 : len(tag)-1], match: false, strong: false},
+		{name: "empty", header: "", match: false, strong: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match([]byte(tt.header), tag); got != tt.match {
+				t.Errorf("Match(%q) = %v, want %v", tt.header, got, tt.match)
+			}
+			if got := MatchStrong([]byte(tt.header), tag); got != tt.strong {
+				t.Errorf("MatchStrong(%q) = %v, want %v", tt.header, got, tt.strong)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package identity
+
+import (
+	"context"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"strings"
+)
+
+type ModerationChecker interface {
+	CanModerate(ctx context.Context, slug string, nickname string) (bool, error)
+}
+
+func Caller(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(constants.CallerKey).(string)
+	return caller, ok && caller != ""
+}
+
+func Is(ctx context.Context, nickname string) bool {
+	caller, ok := Caller(ctx)
+	return ok && strings.EqualFold(caller, nickname)
+}
+
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(constants.AdminKey).(bool)
+	return admin
+}
+
+// isGlobalModerator tells whether the caller moderates every forum, as
+// admins and holders of the moderation token do.
+func isGlobalModerator(ctx context.Context) bool {
+	privileged, _ := ctx.Value(constants.PrivilegedKey).(bool)
+	return privileged || IsAdmin(ctx)
+}
+
+// ActAs rejects requests made by an authenticated caller on behalf of
+// another user. Anonymous requests pass through, as requiring a token
+// is left to the auth middleware.
+func ActAs(ctx context.Context, nickname string) error {
+	if _, ok := Caller(ctx); ok && !Is(ctx, nickname) {
+		return forumErrors.NewForbiddenError("act on behalf of another user")
+	}
+	return nil
+}
+
+// Own lets the caller change something that belongs to owner if they are
+// the owner or an admin. Anonymous callers own nothing.
+func Own(ctx context.Context, owner string) error {
+	if _, ok := Caller(ctx); !ok {
+		return forumErrors.NewUnauthorizedError("authentication required")
+	}
+	if Is(ctx, owner) || IsAdmin(ctx) {
+		return nil
+	}
+	return forumErrors.NewForbiddenError("change what belongs to another user")
+}
+
+// Manage lets the caller change content owned by author in the given
+// forum if they are the author, an admin, the forum owner or one of
+// its moderators. Anonymous callers can manage nothing.
+func Manage(ctx context.Context, checker ModerationChecker, forum string, author string) error {
+	if _, ok := Caller(ctx); !ok {
+		return forumErrors.NewUnauthorizedError("authentication required")
+	}
+	if Is(ctx, author) {
+		return nil
+	}
+
+	canModerate, err := CanModerate(ctx, checker, forum)
+	if err != nil {
+		return err
+	}
+
+	if !canModerate {
+		return forumErrors.NewForbiddenError("change content of another user")
+	}
+	return nil
+}
+
+func CanModerate(ctx context.Context, checker ModerationChecker, forum string) (bool, error) {
+	if isGlobalModerator(ctx) {
+		return true, nil
+	}
+
+	caller, ok := Caller(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	return checker.CanModerate(ctx, forum, caller)
+}
@@ -0,0 +1,534 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/pgxhook"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+const (
+	legacyThread = "thread"
+	legacyPost   = "post"
+
+	queryStageUsers = `CREATE TEMP TABLE import_users (
+							line        BIGINT              NOT NULL,
+							nickname    CITEXT COLLATE "C"  NOT NULL,
+							fullname    TEXT                NOT NULL,
+							about       TEXT,
+							email       CITEXT              NOT NULL
+						) ON COMMIT DROP;`
+	queryInsertUsers = `INSERT INTO users (nickname, fullname, about, email)
+						SELECT DISTINCT ON (nickname) nickname, fullname, about, email
+						  FROM import_users
+						 ORDER BY nickname, line
+						ON CONFLICT DO NOTHING;`
+
+	queryStageForums = `CREATE TEMP TABLE import_forums (
+							line        BIGINT              NOT NULL,
+							title       TEXT                NOT NULL,
+							"user"      CITEXT COLLATE "C"  NOT NULL,
+							slug        CITEXT              NOT NULL
+						) ON COMMIT DROP;`
+	queryInsertForums = `INSERT INTO forums (title, "user", slug)
+						 SELECT DISTINCT ON (s.slug) s.title, u.nickname, s.slug
+						   FROM import_forums s
+						   JOIN users u ON u.nickname = s."user"
+						  ORDER BY s.slug, s.line
+						 ON CONFLICT DO NOTHING;`
+
+	queryStageThreads = `CREATE TEMP TABLE import_threads (
+							 line        BIGINT                      NOT NULL    PRIMARY KEY,
+							 legacy      BIGINT,
+							 title       TEXT                        NOT NULL,
+							 author      CITEXT COLLATE "C"          NOT NULL,
+							 forum       CITEXT                      NOT NULL,
+							 message     TEXT                        NOT NULL,
+							 slug        CITEXT,
+							 created     TIMESTAMP WITH TIME ZONE    NOT NULL,
+							 id          BIGINT
+						 ) ON COMMIT DROP;`
+	queryIndexThreads = `CREATE INDEX ON import_threads (legacy);
+						 CREATE INDEX ON import_threads (slug);
+						 ANALYZE import_threads;`
+	// Only the first occurrence of a slug or a legacy id is kept, and neither
+	// may clash with rows that are already stored.
+	queryResolveThreads = `WITH valid AS (
+							   SELECT s.line, u.nickname, f.slug AS forum
+								 FROM import_threads s
+								 JOIN users u ON u.nickname = s.author
+								 JOIN forums f ON f.slug = s.forum
+								WHERE (s.slug IS NULL OR (
+										  NOT EXISTS (SELECT 1 FROM threads t WHERE t.slug = s.slug)
+									  AND NOT EXISTS (SELECT 1 FROM import_threads d WHERE d.slug = s.slug AND d.line < s.line)
+									  ))
+								  AND (s.legacy IS NULL OR (
+										  NOT EXISTS (SELECT 1 FROM import_ids m WHERE m.source = $1 AND m.kind = $2 AND m.legacy = s.legacy)
+									  AND NOT EXISTS (SELECT 1 FROM import_threads d WHERE d.legacy = s.legacy AND d.line < s.line)
+									  ))
+								ORDER BY s.line
+						   ), numbered AS (
+							   SELECT line, nickname, forum, nextval(pg_get_serial_sequence('threads', 'id')) AS id
+								 FROM valid
+						   )
+						   UPDATE import_threads s
+							  SET author = n.nickname, forum = n.forum, id = n.id
+							 FROM numbered n
+							WHERE s.line = n.line;`
+	queryInsertThreads = `INSERT INTO threads (id, title, author, forum, message, slug, created)
+						  SELECT id, title, author, forum, message, slug, created
+							FROM import_threads
+						   WHERE id IS NOT NULL
+						   ORDER BY line;`
+	queryMapThreads = `INSERT INTO import_ids (source, kind, legacy, id)
+					   SELECT $1, $2, legacy, id
+						 FROM import_threads
+						WHERE id IS NOT NULL AND legacy IS NOT NULL;`
+
+	queryStagePosts = `CREATE TEMP TABLE import_posts (
+						   line            BIGINT                      NOT NULL    PRIMARY KEY,
+						   legacy          BIGINT,
+						   parent_legacy   BIGINT                      NOT NULL,
+						   author          CITEXT COLLATE "C"          NOT NULL,
+						   message         TEXT                        NOT NULL,
+						   is_edited       BOOLEAN                     NOT NULL,
+						   thread_legacy   BIGINT                      NOT NULL,
+						   created         TIMESTAMP WITH TIME ZONE    NOT NULL,
+						   state           TEXT                        NOT NULL,
+						   thread          BIGINT,
+						   forum           CITEXT,
+						   parent          BIGINT,
+						   depth           INTEGER,
+						   id              BIGINT
+					   ) ON COMMIT DROP;`
+	queryIndexPosts = `CREATE INDEX ON import_posts (legacy);
+					   CREATE INDEX ON import_posts (parent_legacy);
+					   ANALYZE import_posts;`
+	queryResolvePostThreads = `UPDATE import_posts s
+								  SET thread = t.id, forum = t.forum, author = u.nickname
+								 FROM import_ids m
+								 JOIN threads t ON t.id = m.id, users u
+								WHERE m.source = $1 AND m.kind = $2 AND m.legacy = s.thread_legacy
+								  AND u.nickname = s.author
+								  AND (s.legacy IS NULL OR (
+										  NOT EXISTS (SELECT 1 FROM import_ids d WHERE d.source = $1 AND d.kind = $3 AND d.legacy = s.legacy)
+									  AND NOT EXISTS (SELECT 1 FROM import_posts d WHERE d.legacy = s.legacy AND d.line < s.line)
+									  ));`
+	// Posts that start a level-by-level load are roots and replies to posts
+	// imported by an earlier run.
+	queryResolveRoots = `UPDATE import_posts
+							SET parent = 0, depth = 0
+						  WHERE thread IS NOT NULL AND parent_legacy = 0;`
+	queryResolveMappedParents = `UPDATE import_posts s
+									SET parent = p.id, depth = 0
+								   FROM import_ids m
+								   JOIN posts p ON p.id = m.id
+								  WHERE s.thread IS NOT NULL AND s.parent_legacy <> 0
+									AND m.source = $1 AND m.kind = $2 AND m.legacy = s.parent_legacy
+									AND p.thread = s.thread;`
+	// Replies to posts of the same file are reachable from a resolved level;
+	// orphans and cycles never are and stay without a depth.
+	queryResolveDepth = `WITH RECURSIVE levels AS (
+							 SELECT line, legacy, thread, depth
+							   FROM import_posts
+							  WHERE depth = 0
+							 UNION ALL
+							 SELECT c.line, c.legacy, c.thread, l.depth + 1
+							   FROM import_posts c
+							   JOIN levels l ON c.parent_legacy = l.legacy AND c.thread = l.thread
+							  WHERE c.depth IS NULL
+						 )
+						 UPDATE import_posts s
+							SET depth = l.depth
+						   FROM levels l
+						  WHERE s.line = l.line AND s.depth IS NULL;`
+	queryAssignPostIds = `WITH numbered AS (
+							  SELECT line, nextval(pg_get_serial_sequence('posts', 'id')) AS id
+								FROM (SELECT line FROM import_posts WHERE depth IS NOT NULL ORDER BY line) v
+						  )
+						  UPDATE import_posts s
+							 SET id = n.id
+							FROM numbered n
+						   WHERE s.line = n.line;`
+	queryResolveParents = `UPDATE import_posts c
+							  SET parent = p.id
+							 FROM import_posts p
+							WHERE c.depth > 0 AND c.parent IS NULL
+							  AND p.legacy = c.parent_legacy AND p.thread = c.thread AND p.depth = c.depth - 1;`
+	queryMaxDepth = `SELECT COALESCE(max(depth), -1) FROM import_posts;`
+	// Levels are inserted one at a time so the path trigger always finds the
+	// parent of a post.
+	queryInsertPosts = `INSERT INTO posts (id, parent, author, message, is_edited, forum, thread, created, state)
+						SELECT id, parent, author, message, is_edited, forum, thread, created, state
+						  FROM import_posts
+						 WHERE depth = $1
+						 ORDER BY line;`
+	queryMapPosts = `INSERT INTO import_ids (source, kind, legacy, id)
+					 SELECT $1, $2, legacy, id
+					   FROM import_posts
+					  WHERE id IS NOT NULL AND legacy IS NOT NULL;`
+
+	queryStageVotes = `CREATE TEMP TABLE import_votes (
+						   line            BIGINT              NOT NULL,
+						   nickname        CITEXT COLLATE "C"  NOT NULL,
+						   thread_legacy   BIGINT              NOT NULL,
+						   voice           INTEGER             NOT NULL
+					   ) ON COMMIT DROP;`
+	// The last vote of a user in a thread wins, both within the file and over
+	// the stored one.
+	queryInsertVotes = `INSERT INTO votes (nickname, thread, voice)
+						SELECT DISTINCT ON (u.nickname, m.id) u.nickname, m.id, s.voice
+						  FROM import_votes s
+						  JOIN users u ON u.nickname = s.nickname
+						  JOIN import_ids m ON m.source = $1 AND m.kind = $2 AND m.legacy = s.thread_legacy
+						 ORDER BY u.nickname, m.id, s.line DESC
+						ON CONFLICT (nickname, thread) DO UPDATE SET voice = EXCLUDED.voice;`
+
+	// The rows a load touched, whose counters FixCounters has to check.
+	queryTouchedThreadForums = `SELECT DISTINCT forum FROM import_threads WHERE id IS NOT NULL;`
+	queryTouchedPostForums   = `SELECT DISTINCT forum FROM import_posts WHERE id IS NOT NULL;`
+	queryTouchedVoteThreads  = `SELECT DISTINCT m.id
+								  FROM import_votes s
+								  JOIN import_ids m ON m.source = $1 AND m.kind = $2 AND m.legacy = s.thread_legacy;`
+
+	queryFixForumCounters = `UPDATE forums f
+								SET threads = c.threads, posts = c.posts
+							   FROM (SELECT slug,
+											(SELECT count(*) FROM threads t WHERE t.forum = s.slug) AS threads,
+											(SELECT count(*) FROM posts p WHERE p.forum = s.slug) AS posts
+									   FROM forums s
+									  WHERE s.slug = ANY($1::text[]::citext[])) c
+							  WHERE f.slug = c.slug
+								AND (f.threads, f.posts) IS DISTINCT FROM (c.threads, c.posts);`
+	queryFixThreadCounters = `UPDATE threads t
+								 SET votes = c.votes
+								FROM (SELECT s.id,
+											 COALESCE((SELECT sum(voice) FROM votes v WHERE v.thread = s.id), 0) AS votes
+										FROM threads s
+									   WHERE s.id = ANY($1::bigint[])) c
+							   WHERE t.id = c.id
+								 AND t.votes IS DISTINCT FROM c.votes;`
+)
+
+var (
+	stageColumns = map[bundle.Kind][]string{
+		bundle.Users:   {"line", "nickname", "fullname", "about", "email"},
+		bundle.Forums:  {"line", "title", "user", "slug"},
+		bundle.Threads: {"line", "legacy", "title", "author", "forum", "message", "slug", "created"},
+		bundle.Posts:   {"line", "legacy", "parent_legacy", "author", "message", "is_edited", "thread_legacy", "created", "state"},
+		bundle.Votes:   {"line", "nickname", "thread_legacy", "voice"},
+	}
+	stageQueries = map[bundle.Kind]string{
+		bundle.Users:   queryStageUsers,
+		bundle.Forums:  queryStageForums,
+		bundle.Threads: queryStageThreads,
+		bundle.Posts:   queryStagePosts,
+		bundle.Votes:   queryStageVotes,
+	}
+	decoders = map[bundle.Kind]func([]byte) ([]interface{}, error){
+		bundle.Users:   decodeUser,
+		bundle.Forums:  decodeForum,
+		bundle.Threads: decodeThread,
+		bundle.Posts:   decodePost,
+		bundle.Votes:   decodeVote,
+	}
+)
+
+type Report struct {
+	Kind     bundle.Kind
+	Read     int64
+	Imported int64
+	Rejected int64
+}
+
+type Importer struct {
+	db       *pgxhook.Pool
+	source   string
+	progress func(kind bundle.Kind, read int64)
+
+	forums  map[string]struct{}
+	threads map[int64]struct{}
+}
+
+// New makes an importer that maps legacy ids under source, so the ids of
+// different systems never clash.
+func New(db *pgxhook.Pool, source string, progress func(kind bundle.Kind, read int64)) *Importer {
+	return &Importer{
+		db:       db,
+		source:   source,
+		progress: progress,
+		forums:   make(map[string]struct{}),
+		threads:  make(map[int64]struct{}),
+	}
+}
+
+// Import loads one NDJSON stream of the given kind in a single transaction.
+// Lines that cannot be decoded or that reference missing rows are rejected
+// without failing the whole load.
+func (i *Importer) Import(ctx context.Context, kind bundle.Kind, reader io.Reader) (Report, error) {
+	return i.importLines(ctx, kind, readLines(reader))
+}
+
+// ImportBundle loads every run of records of the same kind from the bundle
+// in its own transaction, reporting once per kind.
+func (i *Importer) ImportBundle(ctx context.Context, reader bundle.Reader) ([]Report, error) {
+	runs := &bundleRuns{reader: reader}
+	reports := make([]Report, 0, len(bundle.Kinds))
+
+	for {
+		kind, err := runs.peek()
+		if err == io.EOF {
+			return reports, nil
+		}
+		if err != nil {
+			return reports, err
+		}
+
+		report, err := i.importLines(ctx, kind, runs.of(kind))
+		if err != nil {
+			return reports, fmt.Errorf("%s: %w", kind, err)
+		}
+
+		merged := false
+		for idx := range reports {
+			if reports[idx].Kind == kind {
+				reports[idx].Read += report.Read
+				reports[idx].Imported += report.Imported
+				reports[idx].Rejected += report.Rejected
+				merged = true
+			}
+		}
+		if !merged {
+			reports = append(reports, report)
+		}
+	}
+}
+
+func (i *Importer) importLines(ctx context.Context, kind bundle.Kind, next func() ([]byte, error)) (Report, error) {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"importer": "Postgres",
+		"method":   "Import",
+		"kind":     kind,
+	})
+
+	report := Report{Kind: kind}
+
+	decode, ok := decoders[kind]
+	if !ok {
+		return report, fmt.Errorf("unknown import kind: %s", kind)
+	}
+
+	tx, err := i.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, stageQueries[kind]); err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+
+	source := newLineSource(next, decode, func(read int64) {
+		if i.progress != nil {
+			i.progress(kind, read)
+		}
+	})
+
+	staged, err := tx.CopyFrom(ctx, pgx.Identifier{"import_" + string(kind)}, stageColumns[kind], source)
+	if err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+
+	switch kind {
+	case bundle.Users:
+		report.Imported, err = i.exec(ctx, tx, queryInsertUsers)
+	case bundle.Forums:
+		report.Imported, err = i.exec(ctx, tx, queryInsertForums)
+	case bundle.Threads:
+		report.Imported, err = i.loadThreads(ctx, tx)
+	case bundle.Posts:
+		report.Imported, err = i.loadPosts(ctx, tx)
+	case bundle.Votes:
+		report.Imported, err = i.exec(ctx, tx, queryInsertVotes, i.source, legacyThread)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+
+	if err = i.touch(ctx, tx, kind); err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Error(err.Error())
+		return report, err
+	}
+
+	report.Read = source.read
+	report.Rejected = source.rejected + staged - report.Imported
+
+	return report, nil
+}
+
+// FixCounters recomputes the denormalized counters of the forums and threads
+// the loads of this importer touched so they match the loaded rows.
+func (i *Importer) FixCounters(ctx context.Context) error {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"importer": "Postgres",
+		"method":   "FixCounters",
+	})
+
+	tx, err := i.db.Begin(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	forums := make([]string, 0, len(i.forums))
+	for slug := range i.forums {
+		forums = append(forums, slug)
+	}
+	threads := make([]int64, 0, len(i.threads))
+	for id := range i.threads {
+		threads = append(threads, id)
+	}
+
+	if _, err = tx.Exec(ctx, queryFixForumCounters, forums); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if _, err = tx.Exec(ctx, queryFixThreadCounters, threads); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// touch remembers the forums and threads whose counters the load of kind
+// may have changed.
+func (i *Importer) touch(ctx context.Context, tx pgx.Tx, kind bundle.Kind) error {
+	switch kind {
+	case bundle.Threads:
+		return i.touchForums(ctx, tx, queryTouchedThreadForums)
+	case bundle.Posts:
+		return i.touchForums(ctx, tx, queryTouchedPostForums)
+	case bundle.Votes:
+		rows, err := tx.Query(ctx, queryTouchedVoteThreads, i.source, legacyThread)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id int64
+			if err = rows.Scan(&id); err != nil {
+				return err
+			}
+			i.threads[id] = struct{}{}
+		}
+		return rows.Err()
+	}
+
+	return nil
+}
+
+func (i *Importer) touchForums(ctx context.Context, tx pgx.Tx, query string) error {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var slug string
+		if err = rows.Scan(&slug); err != nil {
+			return err
+		}
+		i.forums[slug] = struct{}{}
+	}
+	return rows.Err()
+}
+
+func (i *Importer) loadThreads(ctx context.Context, tx pgx.Tx) (int64, error) {
+	if _, err := tx.Exec(ctx, queryIndexThreads); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolveThreads, i.source, legacyThread); err != nil {
+		return 0, err
+	}
+
+	inserted, err := i.exec(ctx, tx, queryInsertThreads)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.Exec(ctx, queryMapThreads, i.source, legacyThread); err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+func (i *Importer) loadPosts(ctx context.Context, tx pgx.Tx) (int64, error) {
+	if _, err := tx.Exec(ctx, queryIndexPosts); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolvePostThreads, i.source, legacyThread, legacyPost); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolveRoots); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolveMappedParents, i.source, legacyPost); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolveDepth); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryAssignPostIds); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, queryResolveParents); err != nil {
+		return 0, err
+	}
+
+	var maxDepth int32
+	if err := tx.QueryRow(ctx, queryMaxDepth).Scan(&maxDepth); err != nil {
+		return 0, err
+	}
+
+	var inserted int64
+	for depth := int32(0); depth <= maxDepth; depth++ {
+		level, err := i.exec(ctx, tx, queryInsertPosts, depth)
+		if err != nil {
+			return 0, err
+		}
+		inserted += level
+	}
+
+	if _, err := tx.Exec(ctx, queryMapPosts, i.source, legacyPost); err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+func (i *Importer) exec(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) (int64, error) {
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	postsDomain "github.com/rflban/parkmail-dbms/internal/forum/posts/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"time"
+)
+
+var errInvalidRow = errors.New("invalid row")
+
+func decodeUser(line []byte) ([]interface{}, error) {
+	var user models.User
+	if err := json.Unmarshal(line, &user); err != nil {
+		return nil, err
+	}
+	if user.Nickname == nil || *user.Nickname == "" || user.Email == "" {
+		return nil, errInvalidRow
+	}
+
+	return []interface{}{*user.Nickname, user.Fullname, user.About, user.Email}, nil
+}
+
+func decodeForum(line []byte) ([]interface{}, error) {
+	var forum models.Forum
+	if err := json.Unmarshal(line, &forum); err != nil {
+		return nil, err
+	}
+	if forum.Slug == "" || forum.User == "" {
+		return nil, errInvalidRow
+	}
+
+	return []interface{}{forum.Title, forum.User, forum.Slug}, nil
+}
+
+func decodeThread(line []byte) ([]interface{}, error) {
+	var thread models.Thread
+	if err := json.Unmarshal(line, &thread); err != nil {
+		return nil, err
+	}
+	if thread.Author == "" || thread.Forum == nil || *thread.Forum == "" {
+		return nil, errInvalidRow
+	}
+
+	var legacy *int64
+	if thread.Id != nil {
+		id := int64(*thread.Id)
+		legacy = &id
+	}
+
+	var slug *string
+	if thread.Slug != nil && *thread.Slug != "" {
+		slug = thread.Slug
+	}
+
+	created := time.Now()
+	if thread.Created != nil {
+		created = *thread.Created
+	}
+
+	return []interface{}{legacy, thread.Title, thread.Author, *thread.Forum, thread.Message, slug, created}, nil
+}
+
+func decodePost(line []byte) ([]interface{}, error) {
+	var post models.Post
+	if err := json.Unmarshal(line, &post); err != nil {
+		return nil, err
+	}
+	if post.Author == "" || post.Thread == nil {
+		return nil, errInvalidRow
+	}
+
+	var parent int64
+	if post.Parent != nil {
+		parent = *post.Parent
+	}
+
+	isEdited := false
+	if post.IsEdited != nil {
+		isEdited = *post.IsEdited
+	}
+
+	created := time.Now()
+	if post.Created != nil {
+		created = *post.Created
+	}
+
+	state := postsDomain.StateVisible
+	if post.State != nil {
+		state = *post.State
+	}
+	if !postsDomain.IsValidState(state) {
+		return nil, errInvalidRow
+	}
+
+	return []interface{}{post.Id, parent, post.Author, post.Message, isEdited, int64(*post.Thread), created, state}, nil
+}
+
+func decodeVote(line []byte) ([]interface{}, error) {
+	var vote bundle.Vote
+	if err := json.Unmarshal(line, &vote); err != nil {
+		return nil, err
+	}
+	if vote.Nickname == "" || vote.Thread == 0 || (vote.Voice != 1 && vote.Voice != -1) {
+		return nil, errInvalidRow
+	}
+
+	return []interface{}{vote.Nickname, vote.Thread, vote.Voice}, nil
+}
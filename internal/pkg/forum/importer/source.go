@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/bundle"
+	"io"
+)
+
+const progressEvery = 100_000
+
+// lineSource streams NDJSON lines into CopyFrom. Lines that fail to decode
+// are counted as rejected and skipped; every row is prefixed by its line
+// number so that staged rows keep the order of the file.
+type lineSource struct {
+	next     func() ([]byte, error)
+	decode   func(line []byte) ([]interface{}, error)
+	progress func(read int64)
+
+	read     int64
+	rejected int64
+	row      []interface{}
+	err      error
+}
+
+func newLineSource(next func() ([]byte, error), decode func([]byte) ([]interface{}, error), progress func(int64)) *lineSource {
+	return &lineSource{
+		next:     next,
+		decode:   decode,
+		progress: progress,
+	}
+}
+
+// readLines splits reader into lines, returning io.EOF after the last one.
+func readLines(reader io.Reader) func() ([]byte, error) {
+	buffered := bufio.NewReaderSize(reader, 1<<20)
+
+	return func() ([]byte, error) {
+		line, err := buffered.ReadBytes('\n')
+		if err == io.EOF && len(line) != 0 {
+			return line, nil
+		}
+		return line, err
+	}
+}
+
+func (s *lineSource) Next() bool {
+	for {
+		raw, err := s.next()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			s.err = err
+			return false
+		}
+
+		line := bytes.TrimSpace(raw)
+		if len(line) == 0 {
+			continue
+		}
+
+		s.read++
+		if s.progress != nil && s.read%progressEvery == 0 {
+			s.progress(s.read)
+		}
+
+		row, err := s.decode(line)
+		if err == nil {
+			s.row = append([]interface{}{s.read}, row...)
+			return true
+		}
+		s.rejected++
+	}
+}
+
+func (s *lineSource) Values() ([]interface{}, error) {
+	return s.row, nil
+}
+
+func (s *lineSource) Err() error {
+	return s.err
+}
+
+// bundleRuns splits a bundle into runs of records of the same kind.
+type bundleRuns struct {
+	reader bundle.Reader
+
+	peeked bool
+	kind   bundle.Kind
+	data   []byte
+	err    error
+}
+
+func (r *bundleRuns) peek() (bundle.Kind, error) {
+	if !r.peeked {
+		r.kind, r.data, r.err = r.reader.Next()
+		r.peeked = true
+	}
+	return r.kind, r.err
+}
+
+// of yields the records of the current run and io.EOF once the kind of the
+// bundle changes.
+func (r *bundleRuns) of(kind bundle.Kind) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		next, err := r.peek()
+		if err != nil {
+			return nil, err
+		}
+		if next != kind {
+			return nil, io.EOF
+		}
+
+		r.peeked = false
+		return r.data, nil
+	}
+}
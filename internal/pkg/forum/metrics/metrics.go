@@ -0,0 +1,92 @@
+// Package metrics holds the Prometheus collectors of the service and the
+// handler that exposes them.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"strconv"
+	"time"
+)
+
+const namespace = "forum"
+
+// UnmatchedRoute labels requests that did not match any route, so unknown
+// paths can not blow up the number of series.
+const UnmatchedRoute = "unmatched"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Handled HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time spent handling HTTP requests by method and route pattern.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route"})
+
+	postsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "posts_created_total",
+		Help:      "Posts created through the API.",
+	})
+	threadsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "threads_created_total",
+		Help:      "Threads created through the API.",
+	})
+	votesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_created_total",
+		Help:      "Votes cast through the API, not counting changed ones.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requests,
+		requestDuration,
+		postsCreated,
+		threadsCreated,
+		votesCreated,
+	)
+}
+
+func ObserveRequest(method string, route string, status int, elapsed time.Duration) {
+	requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	requestDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+func PostsCreated(count int) {
+	postsCreated.Add(float64(count))
+}
+
+func ThreadCreated() {
+	threadsCreated.Inc()
+}
+
+func VoteCreated() {
+	votesCreated.Inc()
+}
+
+// Register adds collectors owned by other packages, like the database pool
+// statistics, to the exposed ones.
+func Register(collector prometheus.Collector) error {
+	return registry.Register(collector)
+}
+
+func Handler() fasthttp.RequestHandler {
+	return fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector reads pgxpool statistics at scrape time.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquires          *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool: pool,
+
+		acquiredConns:     desc("acquired_conns", "Connections currently acquired from the pool."),
+		idleConns:         desc("idle_conns", "Idle connections in the pool."),
+		constructingConns: desc("constructing_conns", "Connections being established."),
+		totalConns:        desc("total_conns", "All connections in the pool."),
+		maxConns:          desc("max_conns", "Maximum size of the pool."),
+		acquires:          desc("acquires_total", "Successful acquires from the pool."),
+		acquireDuration:   desc("acquire_duration_seconds_total", "Time spent waiting for successful acquires."),
+		emptyAcquires:     desc("empty_acquires_total", "Successful acquires that had to wait for a connection."),
+		canceledAcquires:  desc("canceled_acquires_total", "Acquires cancelled by their context."),
+	}
+}
+
+func (c *PoolCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.acquiredConns
+	descs <- c.idleConns
+	descs <- c.constructingConns
+	descs <- c.totalConns
+	descs <- c.maxConns
+	descs <- c.acquires
+	descs <- c.acquireDuration
+	descs <- c.emptyAcquires
+	descs <- c.canceledAcquires
+}
+
+func (c *PoolCollector) Collect(metrics chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	metrics <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	metrics <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	metrics <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	metrics <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	metrics <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	metrics <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	metrics <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+const bearerPrefix = "bearer "
+
+type TokenResolver interface {
+	Resolve(ctx context.Context, token string) (string, error)
+	IsAdmin(nickname string) bool
+}
+
+func Auth(resolver TokenResolver, required bool, next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		ctx := rctx.UserValue("ctx").(context.Context)
+		log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+
+		header := rctx.Request.Header.Peek(fasthttp.HeaderAuthorization)
+		if len(header) == 0 {
+			if required {
+				unauthorized(rctx, "authentication required")
+				return
+			}
+
+			next(rctx)
+			return
+		}
+
+		if len(header) <= len(bearerPrefix) || !bytes.EqualFold(header[:len(bearerPrefix)], []byte(bearerPrefix)) {
+			unauthorized(rctx, "invalid authorization header")
+			return
+		}
+
+		nickname, err := resolver.Resolve(ctx, string(bytes.TrimSpace(header[len(bearerPrefix):])))
+		if err != nil {
+			if _, ok := err.(forumErrors.UnauthorizedError); ok {
+				unauthorized(rctx, "invalid or expired token")
+				return
+			}
+
+			log.Error(err.Error())
+
+			body, _ := json.Marshal(models.Error{
+				Message:   "internal server error",
+				RequestId: RequestIdFrom(ctx),
+			})
+
+			rctx.SetContentType("application/json")
+			rctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			rctx.SetBody(body)
+			return
+		}
+
+		ctx = context.WithValue(ctx, constants.CallerKey, nickname)
+		ctx = context.WithValue(ctx, constants.AdminKey, resolver.IsAdmin(nickname))
+		rctx.SetUserValue("ctx", ctx)
+
+		next(rctx)
+	}
+}
+
+func unauthorized(rctx *fasthttp.RequestCtx, message string) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	body, _ := json.Marshal(models.Error{
+		Message:   message,
+		RequestId: RequestIdFrom(ctx),
+	})
+
+	rctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+	rctx.SetContentType("application/json")
+	rctx.SetStatusCode(fasthttp.StatusUnauthorized)
+	rctx.SetBody(body)
+}
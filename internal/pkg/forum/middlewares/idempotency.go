@@ -0,0 +1,131 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	idempotencyDomain "github.com/rflban/parkmail-dbms/internal/forum/idempotency/domain"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	forumErrors "github.com/rflban/parkmail-dbms/internal/pkg/forum/errors"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/identity"
+	"github.com/rflban/parkmail-dbms/pkg/forum/models"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// notReplayedHeaders are the response headers that describe one exchange
+// rather than the response itself, so they are not stored for replay.
+var notReplayedHeaders = map[string]bool{
+	fasthttp.HeaderContentType:      true,
+	fasthttp.HeaderContentLength:    true,
+	fasthttp.HeaderServer:           true,
+	fasthttp.HeaderDate:             true,
+	fasthttp.HeaderConnection:       true,
+	fasthttp.HeaderTransferEncoding: true,
+	fasthttp.HeaderSetCookie:        true,
+	RequestIdHeader:                 true,
+	idempotentReplayedHeader:        true,
+}
+
+type IdempotencyStore interface {
+	Begin(ctx context.Context, key, scope, fingerprint string) (idempotencyDomain.Response, error)
+	Complete(ctx context.Context, key, scope string, response idempotencyDomain.Response) error
+	Release(ctx context.Context, key, scope, claim string) error
+}
+
+// Idempotency replays the stored response of a request repeated with the same
+// Idempotency-Key. Keys are scoped by method, URI and caller, and reusing one
+// with another body is rejected. Server errors are not stored so that the
+// request can be retried.
+func Idempotency(store IdempotencyStore, next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		key := string(rctx.Request.Header.Peek(idempotencyKeyHeader))
+		if key == "" {
+			next(rctx)
+			return
+		}
+
+		ctx := rctx.UserValue("ctx").(context.Context)
+		log := ctx.Value(constants.DeliveryLogKey).(*logrus.Entry)
+
+		if len(key) > maxIdempotencyKeyLength {
+			idempotencyError(rctx, fasthttp.StatusBadRequest, "idempotency key is too long")
+			return
+		}
+
+		caller, _ := identity.Caller(ctx)
+		scope := string(rctx.Method()) + " " + string(rctx.RequestURI()) + " " + caller
+		fingerprint := sha256.Sum256(rctx.PostBody())
+
+		stored, err := store.Begin(ctx, key, scope, hex.EncodeToString(fingerprint[:]))
+		if err != nil {
+			switch err.(type) {
+			case forumErrors.InvalidArgumentError:
+				idempotencyError(rctx, fasthttp.StatusUnprocessableEntity, "idempotency key was used with another request body")
+			case forumErrors.ConflictError:
+				idempotencyError(rctx, fasthttp.StatusConflict, "request with this idempotency key is in progress")
+			default:
+				log.Error(err.Error())
+				idempotencyError(rctx, fasthttp.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		if stored.Status != 0 {
+			for name, value := range stored.Headers {
+				rctx.Response.Header.Set(name, value)
+			}
+			rctx.Response.Header.Set(idempotentReplayedHeader, "true")
+			rctx.SetContentType(stored.ContentType)
+			rctx.SetStatusCode(stored.Status)
+			rctx.SetBody(stored.Body)
+			return
+		}
+
+		next(rctx)
+
+		if rctx.Response.StatusCode() >= fasthttp.StatusInternalServerError {
+			if err := store.Release(ctx, key, scope, stored.Claim); err != nil {
+				log.Error(err.Error())
+			}
+			return
+		}
+
+		headers := make(map[string]string)
+		rctx.Response.Header.VisitAll(func(name, value []byte) {
+			if !notReplayedHeaders[string(name)] {
+				headers[string(name)] = string(value)
+			}
+		})
+
+		err = store.Complete(ctx, key, scope, idempotencyDomain.Response{
+			Claim:       stored.Claim,
+			Status:      rctx.Response.StatusCode(),
+			ContentType: string(rctx.Response.Header.ContentType()),
+			Headers:     headers,
+			Body:        append([]byte(nil), rctx.Response.Body()...),
+		})
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+func idempotencyError(rctx *fasthttp.RequestCtx, status int, message string) {
+	ctx := rctx.UserValue("ctx").(context.Context)
+	body, _ := json.Marshal(models.Error{
+		Message:   message,
+		RequestId: RequestIdFrom(ctx),
+	})
+
+	rctx.SetContentType("application/json")
+	rctx.SetStatusCode(status)
+	rctx.SetBody(body)
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"sync/atomic"
+)
+
+// SampleLogs lets only every n-th request log below warning level. The
+// other requests get loggers whose level is raised to warning, so that
+// their debug and info entries are never built; warnings and errors are
+// always written. It reads the loggers from ctx once and must run before
+// RequestId, which tags whichever loggers it finds.
+func SampleLogs(ctx context.Context, every int, next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	if every <= 1 {
+		return next
+	}
+
+	quiet := make(map[string]*logrus.Entry, len(requestLogKeys))
+	for _, key := range requestLogKeys {
+		if log, ok := ctx.Value(key).(*logrus.Entry); ok {
+			quiet[key] = quietEntry(log)
+		}
+	}
+
+	var seen uint64
+	return func(rctx *fasthttp.RequestCtx) {
+		if atomic.AddUint64(&seen, 1)%uint64(every) == 1 {
+			next(rctx)
+			return
+		}
+
+		ctx := rctx.UserValue("ctx").(context.Context)
+		for key, log := range quiet {
+			ctx = context.WithValue(ctx, key, log)
+		}
+		rctx.SetUserValue("ctx", ctx)
+
+		next(rctx)
+	}
+}
+
+// quietEntry copies log with its level raised to warning.
+func quietEntry(log *logrus.Entry) *logrus.Entry {
+	level := log.Logger.GetLevel()
+	if level > logrus.WarnLevel {
+		level = logrus.WarnLevel
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(log.Logger.Out)
+	logger.SetFormatter(log.Logger.Formatter)
+	logger.ReplaceHooks(log.Logger.Hooks)
+	logger.SetReportCaller(log.Logger.ReportCaller)
+	logger.SetLevel(level)
+
+	return logrus.NewEntry(logger).WithFields(log.Data)
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	FasthttpRouter "github.com/fasthttp/router"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/metrics"
+	"github.com/valyala/fasthttp"
+	"time"
+)
+
+// Metrics records every request under the pattern of the route it matched,
+// which the router only saves with SaveMatchedRoutePath enabled.
+func Metrics(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		started := time.Now()
+		next(rctx)
+
+		route, ok := rctx.UserValue(FasthttpRouter.MatchedRoutePathParam).(string)
+		if !ok {
+			route = metrics.UnmatchedRoute
+		}
+
+		metrics.ObserveRequest(string(rctx.Method()), route, rctx.Response.StatusCode(), time.Since(started))
+	}
+}
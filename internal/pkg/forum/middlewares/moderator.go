@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/valyala/fasthttp"
+)
+
+const moderatorTokenHeader = "X-Moderator-Token"
+
+func Moderator(token string, next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		provided := rctx.Request.Header.Peek(moderatorTokenHeader)
+
+		if token != "" && subtle.ConstantTimeCompare(provided, []byte(token)) == 1 {
+			ctx := rctx.UserValue("ctx").(context.Context)
+			rctx.SetUserValue("ctx", context.WithValue(ctx, constants.PrivilegedKey, true))
+		}
+
+		next(rctx)
+	}
+}
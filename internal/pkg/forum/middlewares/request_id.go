@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	RequestIdHeader    = "X-Request-ID"
+	maxRequestIdLength = 128
+)
+
+var requestLogKeys = []string{
+	constants.AccessLogKey,
+	constants.DeliveryLogKey,
+	constants.UseCaseLogKey,
+	constants.RepoLogKey,
+}
+
+// RequestId takes the request ID the client sent or makes one up, puts it
+// into the context every layer reads, tags their loggers with it and
+// returns it in the response headers. Handlers put it into error bodies
+// with RequestIdFrom.
+func RequestId(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		ctx := rctx.UserValue("ctx").(context.Context)
+
+		requestId := string(rctx.Request.Header.Peek(RequestIdHeader))
+		if !isValidRequestId(requestId) {
+			requestId = uuid.New().String()
+		}
+
+		ctx = context.WithValue(ctx, constants.RequestIdKey, requestId)
+		for _, key := range requestLogKeys {
+			if log, ok := ctx.Value(key).(*logrus.Entry); ok {
+				ctx = context.WithValue(ctx, key, log.WithField("request_id", requestId))
+			}
+		}
+
+		rctx.SetUserValue("ctx", ctx)
+		rctx.Response.Header.Set(RequestIdHeader, requestId)
+
+		next(rctx)
+	}
+}
+
+// RequestIdFrom returns the request ID RequestId put into ctx, or an empty
+// string outside of a request.
+func RequestIdFrom(ctx context.Context) string {
+	requestId, _ := ctx.Value(constants.RequestIdKey).(string)
+	return requestId
+}
+
+// isValidRequestId accepts only IDs that are safe to echo into headers,
+// logs and JSON as they are.
+func isValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+
+	for _, c := range []byte(requestId) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
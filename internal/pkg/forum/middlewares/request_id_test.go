@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"context"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"strings"
+	"testing"
+)
+
+func TestIsValidRequestId(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestId string
+		want      bool
+	}{
+		{name: "uuid", requestId: "3f1c2a9e-8d4b-4c1e-9f0a-2b7d6e5c4a31", want: true},
+		{name: "allowed punctuation", requestId: "trace_1.span:2", want: true},
+		{name: "longest", requestId: strings.Repeat("a", maxRequestIdLength), want: true},
+		{name: "empty", requestId: "", want: false},
+		{name: "too long", requestId: strings.Repeat("a", maxRequestIdLength+1), want: false},
+		{name: "space", requestId: "a b", want: false},
+		{name: "header injection", requestId: "a\r\nSet-Cookie: x", want: false},
+		{name: "quote", requestId: `a"b`, want: false},
+		{name: "non ascii", requestId: "ид", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidRequestId(tt.requestId); got != tt.want {
+				t.Errorf("isValidRequestId(%q) = %v, want %v", tt.requestId, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestIdFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "outside of a request", ctx: context.Background(), want: ""},
+		{name: "in a request", ctx: context.WithValue(context.Background(), constants.RequestIdKey, "abc"), want: "abc"},
+		{name: "wrong type", ctx: context.WithValue(context.Background(), constants.RequestIdKey, 1), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequestIdFrom(tt.ctx); got != tt.want {
+				t.Errorf("RequestIdFrom() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
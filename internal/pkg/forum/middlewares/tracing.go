@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"context"
+	FasthttpRouter "github.com/fasthttp/router"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/tracing"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens the server span of a request, continuing the trace of the
+// caller if it sent one. The span is renamed after the matched route once
+// the router is done, as raw paths would make every span name unique.
+func Tracing(next func(*fasthttp.RequestCtx)) func(*fasthttp.RequestCtx) {
+	return func(rctx *fasthttp.RequestCtx) {
+		ctx := rctx.UserValue("ctx").(context.Context)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, requestHeaderCarrier{&rctx.Request.Header})
+
+		method := string(rctx.Method())
+		ctx, span := tracing.Tracer().Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethodKey.String(method),
+				semconv.HTTPTargetKey.String(string(rctx.RequestURI())),
+			),
+		)
+		defer span.End()
+
+		rctx.SetUserValue("ctx", ctx)
+		next(rctx)
+
+		if route, ok := rctx.UserValue(FasthttpRouter.MatchedRoutePathParam).(string); ok {
+			span.SetName(method + " " + route)
+			span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		}
+
+		status := rctx.Response.StatusCode()
+		span.SetAttributes(
+			semconv.HTTPStatusCodeKey.Int(status),
+			attribute.String("http.request_id", string(rctx.Response.Header.Peek(RequestIdHeader))),
+		)
+		if status >= fasthttp.StatusInternalServerError {
+			span.SetStatus(codes.Error, fasthttp.StatusMessage(status))
+		}
+	}
+}
+
+type requestHeaderCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (c requestHeaderCarrier) Get(key string) string {
+	return string(c.header.Peek(key))
+}
+
+func (c requestHeaderCarrier) Set(key string, value string) {
+	c.header.Set(key, value)
+}
+
+func (c requestHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
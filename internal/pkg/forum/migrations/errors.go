@@ -0,0 +1,57 @@
+package migrations
+
+import "fmt"
+
+type ChecksumMismatchError struct {
+	version int64
+	name    string
+}
+
+func NewChecksumMismatchError(version int64, name string) ChecksumMismatchError {
+	return ChecksumMismatchError{
+		version: version,
+		name:    name,
+	}
+}
+
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("Migration %d_%s was modified after it had been applied", e.version, e.name)
+}
+
+type IrreversibleMigrationError struct {
+	version int64
+	name    string
+}
+
+func NewIrreversibleMigrationError(version int64, name string) IrreversibleMigrationError {
+	return IrreversibleMigrationError{
+		version: version,
+		name:    name,
+	}
+}
+
+func (e IrreversibleMigrationError) Error() string {
+	return fmt.Sprintf("Migration %d_%s has no down script", e.version, e.name)
+}
+
+type MigrationFailedError struct {
+	version int64
+	name    string
+	cause   error
+}
+
+func NewMigrationFailedError(version int64, name string, cause error) MigrationFailedError {
+	return MigrationFailedError{
+		version: version,
+		name:    name,
+		cause:   cause,
+	}
+}
+
+func (e MigrationFailedError) Error() string {
+	return fmt.Sprintf("Migration %d_%s failed: %s", e.version, e.name, e.cause.Error())
+}
+
+func (e MigrationFailedError) Unwrap() error {
+	return e.cause
+}
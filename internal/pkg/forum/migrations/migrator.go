@@ -0,0 +1,296 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	advisoryLockId = 7_402_315_961
+
+	queryCreateTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+							version     BIGINT                      NOT NULL    PRIMARY KEY,
+							name        TEXT                        NOT NULL,
+							checksum    TEXT                        NOT NULL,
+							applied_at  TIMESTAMP WITH TIME ZONE    DEFAULT now()
+						);`
+	queryLock       = `SELECT pg_advisory_lock($1);`
+	queryUnlock     = `SELECT pg_advisory_unlock($1);`
+	queryGetApplied = `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version;`
+	queryInsert     = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3);`
+	queryDelete     = `DELETE FROM schema_migrations WHERE version = $1;`
+)
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type State struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+	Missing   bool
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(db *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"migrator": "Postgres",
+		"method":   "Up",
+	})
+
+	conn, err := m.lock(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer m.unlock(ctx, conn)
+
+	if _, err = conn.Exec(ctx, queryCreateTable); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if record, ok := applied[migration.Version]; ok && record.Checksum != migration.Checksum {
+			err = NewChecksumMismatchError(migration.Version, migration.Name)
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	done := make([]Migration, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		log.Infof("applying %d_%s", migration.Version, migration.Name)
+
+		if err = m.apply(ctx, conn, migration.Up, queryInsert, migration.Version, migration.Name, migration.Checksum); err != nil {
+			log.Error(err.Error())
+			return done, NewMigrationFailedError(migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"migrator": "Postgres",
+		"method":   "Down",
+	})
+
+	conn, err := m.lock(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer m.unlock(ctx, conn)
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	done := make([]Migration, 0, steps)
+
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == "" {
+			err = NewIrreversibleMigrationError(migration.Version, migration.Name)
+			log.Error(err.Error())
+			return done, err
+		}
+
+		log.Infof("reverting %d_%s", migration.Version, migration.Name)
+
+		if err = m.apply(ctx, conn, migration.Down, queryDelete, migration.Version); err != nil {
+			log.Error(err.Error())
+			return done, NewMigrationFailedError(migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"migrator": "Postgres",
+		"method":   "Status",
+	})
+
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	defer conn.Release()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	states := make([]State, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+
+		state := State{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+
+		if record, ok := applied[migration.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = record.AppliedAt
+			state.Modified = record.Checksum != migration.Checksum
+		}
+
+		states = append(states, state)
+	}
+
+	for version, record := range applied {
+		if known[version] {
+			continue
+		}
+
+		states = append(states, State{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Missing:   true,
+		})
+	}
+
+	return states, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (*pgxpool.Conn, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.Exec(ctx, queryLock, advisoryLockId); err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (m *Migrator) unlock(ctx context.Context, conn *pgxpool.Conn) {
+	log := ctx.Value(constants.SetupLogKey).(*logrus.Entry).WithFields(logrus.Fields{
+		"migrator": "Postgres",
+		"method":   "unlock",
+	})
+
+	if _, err := conn.Exec(ctx, queryUnlock, advisoryLockId); err != nil {
+		log.Error(err.Error())
+	}
+	conn.Release()
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	applied := make(map[int64]appliedMigration)
+
+	rows, err := conn.Query(ctx, queryGetApplied)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return applied, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	record := appliedMigration{}
+
+	for rows.Next() {
+		err = rows.Scan(
+			&record.Version,
+			&record.Name,
+			&record.Checksum,
+			&record.AppliedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		applied[record.Version] = record
+	}
+
+	if err = rows.Err(); err != nil {
+		if isUndefinedTable(err) {
+			return applied, nil
+		}
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, script string, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, script); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, bookkeeping, args...); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func isUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.SQLState() == "42P01"
+}
@@ -0,0 +1,199 @@
+// Package pgxhook wraps a pool so that every statement it runs is reported
+// to hooks, which is how queries get traced and slow ones get logged.
+// Unlike a pgx logger, a hook is handed the statement as it is, so nothing
+// is copied or formatted for statements no hook cares about.
+package pgxhook
+
+import (
+	"context"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Hook is told about a statement before it runs. The function it returns,
+// if any, is called with the error of the statement once it is done; for a
+// query that is when its rows are closed.
+type Hook interface {
+	Start(ctx context.Context, op string, sql string, args []interface{}) func(err error)
+}
+
+// Pool is a pgxpool.Pool that reports the statements run on it and on its
+// transactions to the hooks.
+type Pool struct {
+	*pgxpool.Pool
+	hooks []Hook
+}
+
+func New(pool *pgxpool.Pool, hooks ...Hook) *Pool {
+	return &Pool{
+		Pool:  pool,
+		hooks: hooks,
+	}
+}
+
+func (p *Pool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	done := start(ctx, p.hooks, "Exec", sql, args)
+	tag, err := p.Pool.Exec(ctx, sql, args...)
+	done(err)
+
+	return tag, err
+}
+
+func (p *Pool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return query(p.Pool.Query, ctx, p.hooks, sql, args)
+}
+
+func (p *Pool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if len(p.hooks) == 0 {
+		return p.Pool.QueryRow(ctx, sql, args...)
+	}
+	return queryRow(p.Pool.Query, ctx, p.hooks, sql, args)
+}
+
+func (p *Pool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (p *Pool) BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error) {
+	inner, err := p.Pool.BeginTx(ctx, options)
+	if err != nil || len(p.hooks) == 0 {
+		return inner, err
+	}
+
+	return &tx{Tx: inner, hooks: p.hooks}, nil
+}
+
+type tx struct {
+	pgx.Tx
+	hooks []Hook
+}
+
+func (t *tx) Begin(ctx context.Context) (pgx.Tx, error) {
+	inner, err := t.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tx{Tx: inner, hooks: t.hooks}, nil
+}
+
+func (t *tx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	done := start(ctx, t.hooks, "Exec", sql, args)
+	tag, err := t.Tx.Exec(ctx, sql, args...)
+	done(err)
+
+	return tag, err
+}
+
+func (t *tx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return query(t.Tx.Query, ctx, t.hooks, sql, args)
+}
+
+func (t *tx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return queryRow(t.Tx.Query, ctx, t.hooks, sql, args)
+}
+
+func (t *tx) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, source pgx.CopyFromSource) (int64, error) {
+	done := start(ctx, t.hooks, "CopyFrom", "COPY "+table.Sanitize()+" FROM STDIN", nil)
+	copied, err := t.Tx.CopyFrom(ctx, table, columns, source)
+	done(err)
+
+	return copied, err
+}
+
+type queryFunc func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+
+func query(run queryFunc, ctx context.Context, hooks []Hook, sql string, args []interface{}) (pgx.Rows, error) {
+	if len(hooks) == 0 {
+		return run(ctx, sql, args...)
+	}
+
+	done := start(ctx, hooks, "Query", sql, args)
+	inner, err := run(ctx, sql, args...)
+	if err != nil {
+		done(err)
+		return inner, err
+	}
+
+	return &rows{Rows: inner, done: done}, nil
+}
+
+// queryRow mirrors pgx: the row is a query whose first row is scanned.
+func queryRow(run queryFunc, ctx context.Context, hooks []Hook, sql string, args []interface{}) pgx.Row {
+	inner, _ := query(run, ctx, hooks, sql, args)
+	return row{rows: inner}
+}
+
+// rows reports the query once it is closed, which pgx does by itself after
+// the last row has been read.
+type rows struct {
+	pgx.Rows
+	done func(err error)
+}
+
+func (r *rows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.Close()
+	return false
+}
+
+func (r *rows) Close() {
+	r.Rows.Close()
+	if r.done != nil {
+		r.done(r.Rows.Err())
+		r.done = nil
+	}
+}
+
+type row struct {
+	rows pgx.Rows
+}
+
+func (r row) Scan(dest ...interface{}) error {
+	defer r.rows.Close()
+
+	if err := r.rows.Err(); err != nil {
+		return err
+	}
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+
+	r.rows.Scan(dest...)
+	r.rows.Close()
+
+	return r.rows.Err()
+}
+
+// start tells the hooks about a statement and returns the function that
+// tells them it is done.
+func start(ctx context.Context, hooks []Hook, op string, sql string, args []interface{}) func(err error) {
+	switch len(hooks) {
+	case 0:
+		return func(error) {}
+	case 1:
+		if done := hooks[0].Start(ctx, op, sql, args); done != nil {
+			return done
+		}
+		return func(error) {}
+	}
+
+	dones := make([]func(err error), 0, len(hooks))
+	for _, hook := range hooks {
+		if done := hook.Start(ctx, op, sql, args); done != nil {
+			dones = append(dones, done)
+		}
+	}
+
+	return func(err error) {
+		for _, done := range dones {
+			done(err)
+		}
+	}
+}
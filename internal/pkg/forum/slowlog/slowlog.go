@@ -0,0 +1,279 @@
+// Package slowlog logs statements that take longer than a threshold and,
+// optionally, captures the plans of some of them.
+package slowlog
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/rflban/parkmail-dbms/internal/pkg/forum/constants"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	explainTimeout = 30 * time.Second
+	maxArgLength   = 64
+)
+
+type Config struct {
+	// Threshold is the duration from which a statement is slow. Zero turns
+	// the log off.
+	Threshold time.Duration
+	// Explain runs EXPLAIN (ANALYZE, BUFFERS) on every ExplainEvery-th slow
+	// read statement.
+	Explain      bool
+	ExplainEvery int
+	// Keep is how many of the latest slow statements are remembered.
+	Keep int
+}
+
+func (conf Config) Enabled() bool {
+	return conf.Threshold > 0
+}
+
+type Entry struct {
+	Id        uint64
+	Sql       string
+	Args      []interface{}
+	Duration  time.Duration
+	RequestId string
+	Captured  time.Time
+	Plan      string
+}
+
+// Log is a pgxhook.Hook that keeps the latest slow statements. Plans are
+// captured in the background on a connection of their own, one at a time;
+// slow statements coming in while a plan is being captured are not
+// explained.
+type Log struct {
+	conf       Config
+	log        *logrus.Entry
+	pool       *pgxpool.Pool
+	seen       uint64
+	explaining int32
+
+	mu      sync.Mutex
+	lastId  uint64
+	entries []Entry
+	next    int
+}
+
+func New(conf Config, log *logrus.Entry) *Log {
+	if conf.ExplainEvery < 1 {
+		conf.ExplainEvery = 1
+	}
+	if conf.Keep < 1 {
+		conf.Keep = 1
+	}
+
+	return &Log{
+		conf:    conf,
+		log:     log,
+		entries: make([]Entry, 0, conf.Keep),
+	}
+}
+
+// Attach gives the log the pool plans are captured on. Plans are not
+// captured until it is called.
+func (l *Log) Attach(pool *pgxpool.Pool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pool = pool
+}
+
+// Start times a statement and records it if it turns out to be slow.
+func (l *Log) Start(ctx context.Context, op string, sql string, args []interface{}) func(err error) {
+	if !l.conf.Enabled() {
+		return nil
+	}
+
+	started := time.Now()
+	return func(error) {
+		if took := time.Since(started); took >= l.conf.Threshold {
+			l.record(ctx, sql, copyArgs(args), took)
+		}
+	}
+}
+
+func (l *Log) record(ctx context.Context, sql string, args []interface{}, took time.Duration) {
+	requestId, _ := ctx.Value(constants.RequestIdKey).(string)
+
+	fields := logrus.Fields{
+		"sql":         sql,
+		"args":        args,
+		"duration_ms": float64(took) / float64(time.Millisecond),
+	}
+	if requestId != "" {
+		fields["request_id"] = requestId
+	}
+	l.log.WithFields(fields).Warn("slow query")
+
+	id := l.remember(Entry{
+		Sql:       sql,
+		Args:      args,
+		Duration:  took,
+		RequestId: requestId,
+		Captured:  time.Now(),
+	})
+
+	if l.conf.Explain && isExplainable(sql, args) &&
+		atomic.AddUint64(&l.seen, 1)%uint64(l.conf.ExplainEvery) == 0 {
+		l.explain(id, sql, args)
+	}
+}
+
+// Recent returns the remembered slow statements, the latest first.
+func (l *Log) Recent() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := make([]Entry, 0, len(l.entries))
+	for i := 1; i <= len(l.entries); i++ {
+		recent = append(recent, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+
+	return recent
+}
+
+func (l *Log) remember(entry Entry) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lastId++
+	entry.Id = l.lastId
+
+	if len(l.entries) < l.conf.Keep {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.next] = entry
+	}
+	l.next = (l.next + 1) % l.conf.Keep
+
+	return entry.Id
+}
+
+func (l *Log) setPlan(id uint64, plan string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.entries {
+		if l.entries[i].Id == id {
+			l.entries[i].Plan = plan
+			return
+		}
+	}
+}
+
+func (l *Log) explain(id uint64, sql string, args []interface{}) {
+	l.mu.Lock()
+	pool := l.pool
+	l.mu.Unlock()
+
+	if pool == nil || !atomic.CompareAndSwapInt32(&l.explaining, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&l.explaining, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+		defer cancel()
+
+		plan, err := explain(ctx, pool, sql, args)
+		if err != nil {
+			l.log.WithField("sql", sql).Debugf("slow query can not be explained: %s", err)
+			return
+		}
+
+		l.setPlan(id, plan)
+	}()
+}
+
+// explain runs the statement again under EXPLAIN ANALYZE. The transaction
+// is read only and rolled back, so a statement that writes fails instead
+// of writing twice.
+func explain(ctx context.Context, pool *pgxpool.Pool, sql string, args []interface{}) (string, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+sql, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	lines := make([]string, 0)
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// copyArgs copies the arguments of a slow statement to keep, cutting long
+// ones short the way pgx logs them.
+func copyArgs(args []interface{}) []interface{} {
+	copied := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			if len(v) < maxArgLength {
+				arg = hex.EncodeToString(v)
+			} else {
+				arg = fmt.Sprintf("%x (truncated %d bytes)", v[:maxArgLength], len(v)-maxArgLength)
+			}
+		case string:
+			if len(v) > maxArgLength {
+				arg = fmt.Sprintf("%s (truncated %d bytes)", v[:maxArgLength], len(v)-maxArgLength)
+			}
+		}
+		copied = append(copied, arg)
+	}
+
+	return copied
+}
+
+// isExplainable tells whether sql reads and its kept arguments can stand
+// in for the real ones. Long arguments are cut short, so statements with
+// such arguments are not explained.
+func isExplainable(sql string, args []interface{}) bool {
+	head := strings.ToUpper(strings.TrimSpace(sql))
+	if !strings.HasPrefix(head, "SELECT") && !strings.HasPrefix(head, "WITH") {
+		return false
+	}
+
+	for _, arg := range args {
+		if s, ok := arg.(string); ok && strings.Contains(s, " (truncated ") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FormatArgs renders the arguments of an entry for display.
+func FormatArgs(args []interface{}) []string {
+	formatted := make([]string, 0, len(args))
+	for _, arg := range args {
+		formatted = append(formatted, fmt.Sprint(arg))
+	}
+
+	return formatted
+}
@@ -0,0 +1,57 @@
+package slowlog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsExplainable(t *testing.T) {
+	long := strings.Repeat("x", maxArgLength+1)
+
+	tests := []struct {
+		name string
+		sql  string
+		args []interface{}
+		want bool
+	}{
+		{name: "select", sql: "SELECT 1", want: true},
+		{name: "lower case with spaces", sql: "\n\t select id FROM posts WHERE id = $1", args: []interface{}{int64(1)}, want: true},
+		{name: "with", sql: "WITH t AS (SELECT 1) SELECT * FROM t", want: true},
+		{name: "insert", sql: "INSERT INTO posts (id) VALUES ($1)", args: []interface{}{int64(1)}, want: false},
+		{name: "update", sql: "UPDATE posts SET message = $2 WHERE id = $1", want: false},
+		{name: "short argument", sql: "SELECT $1", args: copyArgs([]interface{}{"short"}), want: true},
+		{name: "cut argument", sql: "SELECT $1", args: copyArgs([]interface{}{long}), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExplainable(tt.sql, tt.args); got != tt.want {
+				t.Errorf("isExplainable(%q, %v) = %v, want %v", tt.sql, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyArgs(t *testing.T) {
+	long := strings.Repeat("x", maxArgLength+2)
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want []interface{}
+	}{
+		{name: "none", args: nil, want: []interface{}{}},
+		{name: "kept as they are", args: []interface{}{int64(1), "a", true, nil}, want: []interface{}{int64(1), "a", true, nil}},
+		{name: "long string", args: []interface{}{long}, want: []interface{}{long[:maxArgLength] + " (truncated 2 bytes)"}},
+		{name: "bytes", args: []interface{}{[]byte{0xab, 0x01}}, want: []interface{}{"ab01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := copyArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("copyArgs(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
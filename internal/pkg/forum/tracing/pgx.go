@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer is a pgxhook.Hook that opens a span for every statement of a
+// traced request. Statements outside of a trace cost nothing but the check.
+type QueryTracer struct{}
+
+func (QueryTracer) Start(ctx context.Context, op string, sql string, args []interface{}) func(err error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return nil
+	}
+
+	_, span := Tracer().Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBStatementKey.String(sql),
+		),
+	)
+
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
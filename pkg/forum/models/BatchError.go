@@ -0,0 +1,8 @@
+package models
+
+//easyjson:json
+type BatchError struct {
+	Message   string     `json:"message"`
+	RequestId string     `json:"request_id,omitempty"`
+	Errors    PostErrors `json:"errors"`
+}
@@ -0,0 +1,99 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson9831f4d6DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *BatchError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "message":
+			out.Message = string(in.String())
+		case "request_id":
+			out.RequestId = string(in.String())
+		case "errors":
+			(out.Errors).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9831f4d6EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in BatchError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	if in.RequestId != "" {
+		const prefix string = ",\"request_id\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestId))
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		(in.Errors).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BatchError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9831f4d6EncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BatchError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9831f4d6EncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BatchError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9831f4d6DecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BatchError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9831f4d6DecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
@@ -0,0 +1,7 @@
+package models
+
+//easyjson:json
+type Credentials struct {
+	Nickname string `json:"nickname"`
+	Password string `json:"password"`
+}
@@ -2,5 +2,6 @@ package models
 
 //easyjson:json
 type Error struct {
-	Message string `json:"message,omitempty"`
+	Message   string `json:"message,omitempty"`
+	RequestId string `json:"request_id,omitempty"`
 }
@@ -38,6 +38,8 @@ func easyjsonEd485518DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 		switch key {
 		case "message":
 			out.Message = string(in.String())
+		case "request_id":
+			out.RequestId = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -58,6 +60,16 @@ func easyjsonEd485518EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		out.RawString(prefix[1:])
 		out.String(string(in.Message))
 	}
+	if in.RequestId != "" {
+		const prefix string = ",\"request_id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RequestId))
+	}
 	out.RawByte('}')
 }
 
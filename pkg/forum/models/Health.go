@@ -0,0 +1,6 @@
+package models
+
+//easyjson:json
+type Health struct {
+	Status string `json:"status"`
+}
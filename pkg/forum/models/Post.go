@@ -12,4 +12,5 @@ type Post struct {
 	Forum    *string    `json:"forum,omitempty"`
 	Thread   *int32     `json:"thread,omitempty"`
 	Created  *time.Time `json:"created,omitempty"`
+	State    *string    `json:"state,omitempty"`
 }
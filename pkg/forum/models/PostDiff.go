@@ -0,0 +1,16 @@
+package models
+
+//easyjson:json
+type PostDiff struct {
+	Post   int64       `json:"post"`
+	From   int32       `json:"from"`
+	To     int32       `json:"to"`
+	Mode   string      `json:"mode"`
+	Chunks []DiffChunk `json:"chunks"`
+}
+
+//easyjson:json
+type DiffChunk struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,218 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *PostDiff) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int64(in.Int64())
+		case "from":
+			out.From = int32(in.Int32())
+		case "to":
+			out.To = int32(in.Int32())
+		case "mode":
+			out.Mode = string(in.String())
+		case "chunks":
+			if in.IsNull() {
+				in.Skip()
+				out.Chunks = nil
+			} else {
+				in.Delim('[')
+				if out.Chunks == nil {
+					if !in.IsDelim(']') {
+						out.Chunks = make([]DiffChunk, 0, 2)
+					} else {
+						out.Chunks = []DiffChunk{}
+					}
+				} else {
+					out.Chunks = (out.Chunks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 DiffChunk
+					(v1).UnmarshalEasyJSON(in)
+					out.Chunks = append(out.Chunks, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in PostDiff) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Post))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.From))
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.To))
+	}
+	{
+		const prefix string = ",\"mode\":"
+		out.RawString(prefix)
+		out.String(string(in.Mode))
+	}
+	{
+		const prefix string = ",\"chunks\":"
+		out.RawString(prefix)
+		if in.Chunks == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Chunks {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostDiff) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostDiff) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostDiff) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostDiff) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
+func easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels1(in *jlexer.Lexer, out *DiffChunk) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "op":
+			out.Op = string(in.String())
+		case "text":
+			out.Text = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels1(out *jwriter.Writer, in DiffChunk) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"op\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Op))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DiffChunk) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DiffChunk) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFef1088fEncodeGithubComRflbanParkmailDbmsPkgForumModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DiffChunk) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DiffChunk) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFef1088fDecodeGithubComRflbanParkmailDbmsPkgForumModels1(l, v)
+}
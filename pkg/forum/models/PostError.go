@@ -0,0 +1,8 @@
+package models
+
+//easyjson:json
+type PostError struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
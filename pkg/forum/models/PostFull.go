@@ -2,8 +2,10 @@ package models
 
 //easyjson:json
 type PostFull struct {
-	Post   *Post   `json:"post,omitempty"`
-	Author *User   `json:"author,omitempty"`
-	Thread *Thread `json:"thread,omitempty"`
-	Forum  *Forum  `json:"forum,omitempty"`
+	Post      *Post   `json:"post,omitempty"`
+	Author    *User   `json:"author,omitempty"`
+	Thread    *Thread `json:"thread,omitempty"`
+	Forum     *Forum  `json:"forum,omitempty"`
+	Revisions *int32  `json:"revisions,omitempty"`
+	Ancestors Posts   `json:"ancestors,omitempty"`
 }
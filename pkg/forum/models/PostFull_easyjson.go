@@ -74,8 +74,20 @@ func easyjson5077f799DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 				if out.Forum == nil {
 					out.Forum = new(Forum)
 				}
-				easyjson5077f799DecodeGithubComRflbanParkmailDbmsPkgForumModels1(in, out.Forum)
+				(*out.Forum).UnmarshalEasyJSON(in)
 			}
+		case "revisions":
+			if in.IsNull() {
+				in.Skip()
+				out.Revisions = nil
+			} else {
+				if out.Revisions == nil {
+					out.Revisions = new(int32)
+				}
+				*out.Revisions = int32(in.Int32())
+			}
+		case "ancestors":
+			(out.Ancestors).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -124,7 +136,27 @@ func easyjson5077f799EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson5077f799EncodeGithubComRflbanParkmailDbmsPkgForumModels1(out, *in.Forum)
+		(*in.Forum).MarshalEasyJSON(out)
+	}
+	if in.Revisions != nil {
+		const prefix string = ",\"revisions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(*in.Revisions))
+	}
+	if len(in.Ancestors) != 0 {
+		const prefix string = ",\"ancestors\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.Ancestors).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
@@ -152,89 +184,3 @@ func (v *PostFull) UnmarshalJSON(data []byte) error {
 func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson5077f799DecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
 }
-func easyjson5077f799DecodeGithubComRflbanParkmailDbmsPkgForumModels1(in *jlexer.Lexer, out *Forum) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "title":
-			out.Title = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "slug":
-			out.Slug = string(in.String())
-		case "posts":
-			if in.IsNull() {
-				in.Skip()
-				out.Posts = nil
-			} else {
-				if out.Posts == nil {
-					out.Posts = new(int64)
-				}
-				*out.Posts = int64(in.Int64())
-			}
-		case "threads":
-			if in.IsNull() {
-				in.Skip()
-				out.Threads = nil
-			} else {
-				if out.Threads == nil {
-					out.Threads = new(int32)
-				}
-				*out.Threads = int32(in.Int32())
-			}
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjson5077f799EncodeGithubComRflbanParkmailDbmsPkgForumModels1(out *jwriter.Writer, in Forum) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"title\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Title))
-	}
-	{
-		const prefix string = ",\"user\":"
-		out.RawString(prefix)
-		out.String(string(in.User))
-	}
-	{
-		const prefix string = ",\"slug\":"
-		out.RawString(prefix)
-		out.String(string(in.Slug))
-	}
-	if in.Posts != nil {
-		const prefix string = ",\"posts\":"
-		out.RawString(prefix)
-		out.Int64(int64(*in.Posts))
-	}
-	if in.Threads != nil {
-		const prefix string = ",\"threads\":"
-		out.RawString(prefix)
-		out.Int32(int32(*in.Threads))
-	}
-	out.RawByte('}')
-}
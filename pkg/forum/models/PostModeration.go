@@ -0,0 +1,6 @@
+package models
+
+//easyjson:json
+type PostModeration struct {
+	State string `json:"state"`
+}
@@ -0,0 +1,9 @@
+package models
+
+//easyjson:json
+type PostNode struct {
+	Post
+	Depth     int32     `json:"depth"`
+	Children  PostNodes `json:"children"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
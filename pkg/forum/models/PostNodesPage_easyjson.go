@@ -0,0 +1,114 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson89e1f426DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *PostNodesPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			(out.Items).UnmarshalEasyJSON(in)
+		case "has_more":
+			out.HasMore = bool(in.Bool())
+		case "total":
+			out.Total = int64(in.Int64())
+		case "next_cursor":
+			if in.IsNull() {
+				in.Skip()
+				out.NextCursor = nil
+			} else {
+				if out.NextCursor == nil {
+					out.NextCursor = new(string)
+				}
+				*out.NextCursor = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson89e1f426EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in PostNodesPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		(in.Items).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"has_more\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasMore))
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Total))
+	}
+	if in.NextCursor != nil {
+		const prefix string = ",\"next_cursor\":"
+		out.RawString(prefix)
+		out.String(string(*in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostNodesPage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson89e1f426EncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostNodesPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson89e1f426EncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostNodesPage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson89e1f426DecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostNodesPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson89e1f426DecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
@@ -0,0 +1,7 @@
+package models
+
+//easyjson:json
+type PostReply struct {
+	Post
+	Unexpanded int64 `json:"unexpanded"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+//easyjson:json
+type PostRevision struct {
+	Number  int32      `json:"number"`
+	Author  string     `json:"author"`
+	Message string     `json:"message"`
+	Created *time.Time `json:"created,omitempty"`
+}
@@ -103,6 +103,16 @@ func easyjson6aa74c22DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 					in.AddError((*out.Created).UnmarshalJSON(data))
 				}
 			}
+		case "state":
+			if in.IsNull() {
+				in.Skip()
+				out.State = nil
+			} else {
+				if out.State == nil {
+					out.State = new(string)
+				}
+				*out.State = string(in.String())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -168,6 +178,11 @@ func easyjson6aa74c22EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		out.RawString(prefix)
 		out.Raw((*in.Created).MarshalJSON())
 	}
+	if in.State != nil {
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(*in.State))
+	}
 	out.RawByte('}')
 }
 
@@ -0,0 +1,7 @@
+package models
+
+//easyjson:json
+type PostsBatch struct {
+	Created Posts      `json:"created"`
+	Errors  PostErrors `json:"errors"`
+}
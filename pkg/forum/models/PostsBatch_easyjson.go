@@ -0,0 +1,92 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8b324c93DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *PostsBatch) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "created":
+			(out.Created).UnmarshalEasyJSON(in)
+		case "errors":
+			(out.Errors).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b324c93EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in PostsBatch) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix[1:])
+		(in.Created).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		(in.Errors).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostsBatch) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8b324c93EncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostsBatch) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8b324c93EncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostsBatch) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8b324c93DecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostsBatch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8b324c93DecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
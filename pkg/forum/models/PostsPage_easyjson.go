@@ -0,0 +1,114 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson101ea64eDecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *PostsPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			(out.Items).UnmarshalEasyJSON(in)
+		case "has_more":
+			out.HasMore = bool(in.Bool())
+		case "total":
+			out.Total = int64(in.Int64())
+		case "next_cursor":
+			if in.IsNull() {
+				in.Skip()
+				out.NextCursor = nil
+			} else {
+				if out.NextCursor == nil {
+					out.NextCursor = new(string)
+				}
+				*out.NextCursor = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson101ea64eEncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in PostsPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		(in.Items).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"has_more\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasMore))
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Total))
+	}
+	if in.NextCursor != nil {
+		const prefix string = ",\"next_cursor\":"
+		out.RawString(prefix)
+		out.String(string(*in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostsPage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson101ea64eEncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostsPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson101ea64eEncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostsPage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson101ea64eDecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostsPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson101ea64eDecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+//easyjson:json
+type SearchResult struct {
+	Type    string     `json:"type"`
+	Id      int64      `json:"id"`
+	Thread  int64      `json:"thread"`
+	Forum   string     `json:"forum"`
+	Author  string     `json:"author"`
+	Title   *string    `json:"title,omitempty"`
+	Snippet string     `json:"snippet"`
+	Rank    float32    `json:"rank"`
+	Created *time.Time `json:"created,omitempty"`
+}
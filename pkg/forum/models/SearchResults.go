@@ -0,0 +1,7 @@
+package models
+
+//easyjson:json
+type SearchResults struct {
+	Items      []SearchResult `json:"items"`
+	NextCursor *string        `json:"next_cursor,omitempty"`
+}
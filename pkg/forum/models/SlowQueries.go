@@ -0,0 +1,4 @@
+package models
+
+//easyjson:json
+type SlowQueries []SlowQuery
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+//easyjson:json
+type SlowQuery struct {
+	Id         uint64    `json:"id"`
+	Sql        string    `json:"sql"`
+	Args       []string  `json:"args"`
+	DurationMs float64   `json:"duration_ms"`
+	RequestId  string    `json:"request_id,omitempty"`
+	Captured   time.Time `json:"captured"`
+	Plan       string    `json:"plan,omitempty"`
+}
@@ -0,0 +1,161 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson2e628e6bDecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.Lexer, out *SlowQuery) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = uint64(in.Uint64())
+		case "sql":
+			out.Sql = string(in.String())
+		case "args":
+			if in.IsNull() {
+				in.Skip()
+				out.Args = nil
+			} else {
+				in.Delim('[')
+				if out.Args == nil {
+					if !in.IsDelim(']') {
+						out.Args = make([]string, 0, 4)
+					} else {
+						out.Args = []string{}
+					}
+				} else {
+					out.Args = (out.Args)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Args = append(out.Args, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "duration_ms":
+			out.DurationMs = float64(in.Float64())
+		case "request_id":
+			out.RequestId = string(in.String())
+		case "captured":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Captured).UnmarshalJSON(data))
+			}
+		case "plan":
+			out.Plan = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2e628e6bEncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwriter.Writer, in SlowQuery) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Uint64(uint64(in.Id))
+	}
+	{
+		const prefix string = ",\"sql\":"
+		out.RawString(prefix)
+		out.String(string(in.Sql))
+	}
+	{
+		const prefix string = ",\"args\":"
+		out.RawString(prefix)
+		if in.Args == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Args {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"duration_ms\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.DurationMs))
+	}
+	if in.RequestId != "" {
+		const prefix string = ",\"request_id\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestId))
+	}
+	{
+		const prefix string = ",\"captured\":"
+		out.RawString(prefix)
+		out.Raw((in.Captured).MarshalJSON())
+	}
+	if in.Plan != "" {
+		const prefix string = ",\"plan\":"
+		out.RawString(prefix)
+		out.String(string(in.Plan))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SlowQuery) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2e628e6bEncodeGithubComRflbanParkmailDbmsPkgForumModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SlowQuery) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2e628e6bEncodeGithubComRflbanParkmailDbmsPkgForumModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SlowQuery) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2e628e6bDecodeGithubComRflbanParkmailDbmsPkgForumModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SlowQuery) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2e628e6bDecodeGithubComRflbanParkmailDbmsPkgForumModels(l, v)
+}
@@ -4,12 +4,13 @@ import "time"
 
 //easyjson:json
 type Thread struct {
-	Id      *int32     `json:"id,omitempty"`
-	Title   string     `json:"title"`
-	Author  string     `json:"author"`
-	Forum   *string    `json:"forum,omitempty"`
-	Message string     `json:"message"`
-	Votes   *int32     `json:"votes,omitempty"`
-	Slug    *string    `json:"slug,omitempty"`
-	Created *time.Time `json:"created,omitempty"`
+	Id       *int32     `json:"id,omitempty"`
+	Title    string     `json:"title"`
+	Author   string     `json:"author"`
+	Forum    *string    `json:"forum,omitempty"`
+	Message  string     `json:"message"`
+	Votes    *int32     `json:"votes,omitempty"`
+	Slug     *string    `json:"slug,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+	Revision *int32     `json:"revision,omitempty"`
 }
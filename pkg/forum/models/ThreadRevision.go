@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+//easyjson:json
+type ThreadRevision struct {
+	Number  int32      `json:"number"`
+	Author  string     `json:"author"`
+	Title   string     `json:"title"`
+	Message string     `json:"message"`
+	Created *time.Time `json:"created,omitempty"`
+}
@@ -2,6 +2,7 @@ package models
 
 //easyjson:json
 type ThreadUpdate struct {
-	Title   *string `json:"title,omitempty"`
-	Message *string `json:"message,omitempty"`
+	Title            *string `json:"title,omitempty"`
+	Message          *string `json:"message,omitempty"`
+	ExpectedRevision *int32  `json:"expected_revision,omitempty"`
 }
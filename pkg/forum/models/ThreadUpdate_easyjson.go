@@ -56,6 +56,16 @@ func easyjsonAa44ca3fDecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 				}
 				*out.Message = string(in.String())
 			}
+		case "expected_revision":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpectedRevision = nil
+			} else {
+				if out.ExpectedRevision == nil {
+					out.ExpectedRevision = new(int32)
+				}
+				*out.ExpectedRevision = int32(in.Int32())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -86,6 +96,16 @@ func easyjsonAa44ca3fEncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		}
 		out.String(string(*in.Message))
 	}
+	if in.ExpectedRevision != nil {
+		const prefix string = ",\"expected_revision\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(*in.ExpectedRevision))
+	}
 	out.RawByte('}')
 }
 
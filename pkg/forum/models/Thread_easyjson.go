@@ -95,6 +95,16 @@ func easyjsonA4b236b8DecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 					in.AddError((*out.Created).UnmarshalJSON(data))
 				}
 			}
+		case "revision":
+			if in.IsNull() {
+				in.Skip()
+				out.Revision = nil
+			} else {
+				if out.Revision == nil {
+					out.Revision = new(int32)
+				}
+				*out.Revision = int32(in.Int32())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -155,6 +165,11 @@ func easyjsonA4b236b8EncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		out.RawString(prefix)
 		out.Raw((*in.Created).MarshalJSON())
 	}
+	if in.Revision != nil {
+		const prefix string = ",\"revision\":"
+		out.RawString(prefix)
+		out.Int32(int32(*in.Revision))
+	}
 	out.RawByte('}')
 }
 
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+//easyjson:json
+type Token struct {
+	Token    string     `json:"token"`
+	Nickname string     `json:"nickname"`
+	Expires  *time.Time `json:"expires,omitempty"`
+}
@@ -6,4 +6,6 @@ type User struct {
 	Fullname string  `json:"fullname"`
 	About    *string `json:"about,omitempty"`
 	Email    string  `json:"email"`
+	// Password is only read at sign up and never written back.
+	Password string `json:"password,omitempty"`
 }
@@ -60,6 +60,8 @@ func easyjson750e3c9dDecodeGithubComRflbanParkmailDbmsPkgForumModels(in *jlexer.
 			}
 		case "email":
 			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -100,6 +102,11 @@ func easyjson750e3c9dEncodeGithubComRflbanParkmailDbmsPkgForumModels(out *jwrite
 		out.RawString(prefix)
 		out.String(string(in.Email))
 	}
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
 	out.RawByte('}')
 }
 
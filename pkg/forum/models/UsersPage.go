@@ -0,0 +1,9 @@
+package models
+
+//easyjson:json
+type UsersPage struct {
+	Items      Users   `json:"items"`
+	HasMore    bool    `json:"has_more"`
+	Total      int64   `json:"total"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+}